@@ -10,7 +10,10 @@ import (
 	"github.com/MostafaSensei106/Riko-Chan/config"
 	"github.com/MostafaSensei106/Riko-Chan/internal/bot"
 	"github.com/MostafaSensei106/Riko-Chan/internal/cache"
+	"github.com/MostafaSensei106/Riko-Chan/internal/calendar"
 	"github.com/MostafaSensei106/Riko-Chan/internal/db"
+	"github.com/MostafaSensei106/Riko-Chan/internal/events"
+	"github.com/MostafaSensei106/Riko-Chan/internal/scheduler"
 	"github.com/MostafaSensei106/Riko-Chan/internal/services"
 	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
 )
@@ -30,39 +33,91 @@ func Execute() {
 	if err != nil {
 		logger.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer database.Close()
+	if sqlDB, err := database.DB(); err == nil {
+		defer sqlDB.Close()
+	}
 
 	// Run migrations
 	if err := db.RunMigrations(database); err != nil {
 		logger.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	// Initialize Redis
-	redisClient := cache.NewRedisClient(cfg.Redis)
-	defer redisClient.Close()
-
 	// Initialize repositories
 	userRepo := db.NewUserRepository(database)
 	messageRepo := db.NewMessageRepository(database)
+	deliveryRepo := db.NewDeliveryRepository(database)
 
 	// Initialize services
-	messageService := services.NewMessageService(messageRepo, redisClient, logger)
+	messageService := services.NewMessageService(messageRepo, logger)
 	notificationService := services.NewNotificationService(cfg, logger)
+	messageService.SetUserRepo(userRepo)
+	messageService.SetDeliveryRepo(deliveryRepo)
+	messageService.SetSeriesRepo(db.NewSeriesRepository(database))
+	messageService.SetSchedulerConfig(cfg.Scheduler)
+	messageService.SetNotificationService(notificationService)
+
+	// Working days: a default weekend plus holiday calendars from disk
+	weekend, err := calendar.ParseWeekend(cfg.Calendar.Weekend)
+	if err != nil {
+		logger.Fatalf("Invalid DEFAULT_WEEKEND: %v", err)
+	}
+	calendars := calendar.NewRegistry(weekend)
+	if err := calendars.LoadDir(cfg.Calendar.HolidaysDir); err != nil {
+		logger.Fatalf("Failed to load holiday calendars: %v", err)
+	}
+	messageService.SetCalendars(calendars)
 
-	// Initialize scheduler
-	scheduler := cache.NewScheduler(redisClient, messageService, logger)
-	go scheduler.Start(context.Background())
+	// Domain events; other subsystems subscribe before the bus starts
+	eventBus := events.NewBus(logger)
+	if cfg.Events.Outbox {
+		eventBus.SetOutbox(db.NewOutboxRepository(database))
+	}
+	messageService.SetEventBus(eventBus)
+
+	webhookService := services.NewWebhookService(db.NewWebhookRepository(database), logger)
+	webhookService.SetConfig(cfg.Webhooks)
+	eventBus.Subscribe("webhooks", webhookService.HandleEvent, services.WebhookEvents...)
+
+	// Initialize scheduler backend; Redis is only needed when selected
+	var backend scheduler.Backend
+	switch cfg.Scheduler.Backend {
+	case "redis":
+		redisClient := cache.NewRedisClient(cfg.Redis)
+		defer redisClient.Close()
+		backend = scheduler.NewRedisBackend(redisClient, logger)
+	case "postgres":
+		backend = scheduler.NewPostgresBackend(database, logger)
+	case "memory":
+		backend = scheduler.NewMemoryBackend()
+	default:
+		logger.Fatalf("Unknown scheduler backend: %s", cfg.Scheduler.Backend)
+	}
+
+	messageScheduler := scheduler.NewScheduler(backend, messageRepo, deliveryRepo, messageService, logger)
+	messageScheduler.SetUserRepo(userRepo)
+	messageService.SetScheduler(messageScheduler)
 
 	// Initialize bot
 	telegramBot, err := bot.NewBot(cfg.Telegram, userRepo, messageService, notificationService, logger)
 	if err != nil {
 		logger.Fatalf("Failed to initialize bot: %v", err)
 	}
+	sender := services.NewTelegramSender(telegramBot.API(), logger)
+	messageService.SetSender(sender)
+	notificationService.SetSender(sender)
+	telegramBot.SetSender(sender)
+	telegramBot.SetWebhookService(webhookService)
 
 	// Graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Start the send queue and the scheduler once delivery is wired up
+	go sender.Start(ctx)
+	go messageScheduler.Start(ctx)
+	go eventBus.Start(ctx)
+	go webhookService.Start(ctx)
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
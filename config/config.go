@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +14,10 @@ type Config struct {
 	Redis        RedisConfig
 	Integrations IntegrationsConfig
 	Security     SecurityConfig
+	Scheduler    SchedulerConfig
+	Events       EventsConfig
+	Calendar     CalendarConfig
+	Webhooks     WebhooksConfig
 	LogLevel     string
 }
 
@@ -61,6 +66,32 @@ type SecurityConfig struct {
 	EncryptionKey string
 }
 
+type SchedulerConfig struct {
+	// Backend selects where jobs are queued: "redis", "postgres" or "memory"
+	Backend string
+	// MisfireThreshold is how late a job may fire before its misfire policy applies
+	MisfireThreshold time.Duration
+	// StaleAfter is how late a one-shot message may be before it is marked missed
+	StaleAfter time.Duration
+}
+
+type CalendarConfig struct {
+	// HolidaysDir holds one ICS or YAML holiday file per country or team
+	HolidaysDir string
+	// Weekend is the default weekend, e.g. "sat,sun" or "fri,sat"
+	Weekend string
+}
+
+type EventsConfig struct {
+	// Outbox stores events before handling them, for at-least-once delivery
+	Outbox bool
+}
+
+type WebhooksConfig struct {
+	// AllowHTTP lets users register plain http endpoints, e.g. for testing
+	AllowHTTP bool
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -68,6 +99,13 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func Load() (*Config, error) {
 	godotenv.Load()
 	port, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
@@ -108,6 +146,21 @@ func Load() (*Config, error) {
 		Security: SecurityConfig{
 			EncryptionKey: getEnv("ENCRYPTION_KEY", ""),
 		},
+		Scheduler: SchedulerConfig{
+			Backend:          getEnv("SCHEDULER_BACKEND", "redis"),
+			MisfireThreshold: getEnvDuration("SCHEDULER_MISFIRE_THRESHOLD", time.Minute),
+			StaleAfter:       getEnvDuration("SCHEDULER_STALE_AFTER", 24*time.Hour),
+		},
+		Events: EventsConfig{
+			Outbox: getEnv("EVENTS_OUTBOX", "false") == "true",
+		},
+		Calendar: CalendarConfig{
+			HolidaysDir: getEnv("HOLIDAYS_DIR", "holidays"),
+			Weekend:     getEnv("DEFAULT_WEEKEND", "sat,sun"),
+		},
+		Webhooks: WebhooksConfig{
+			AllowHTTP: getEnv("WEBHOOKS_ALLOW_HTTP", "false") == "true",
+		},
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 	}
 	return config, nil
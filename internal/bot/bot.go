@@ -3,8 +3,10 @@ package bot
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
 
 	"github.com/MostafaSensei106/Riko-Chan/config"
 	"github.com/MostafaSensei106/Riko-Chan/internal/db"
@@ -16,8 +18,10 @@ import (
 type Bot struct {
 	api                 *tgbotapi.BotAPI
 	userRepo            *db.UserRepository
-	messageService      *services.NotificationService
+	messageService      *services.MessageService
 	notificationService *services.NotificationService
+	sender              *services.TelegramSender
+	webhookService      *services.WebhookService
 	logger              *utils.Logger
 }
 
@@ -36,6 +40,20 @@ func NewBot(cfg config.TelegramConfig, userRepo *db.UserRepository, messageServi
 	}, nil
 }
 
+// API exposes the underlying bot API client so other services can share it.
+func (b *Bot) API() *tgbotapi.BotAPI {
+	return b.api
+}
+
+// SetSender routes replies through the shared rate-limited send queue.
+func (b *Bot) SetSender(sender *services.TelegramSender) {
+	b.sender = sender
+}
+
+func (b *Bot) SetWebhookService(webhookService *services.WebhookService) {
+	b.webhookService = webhookService
+}
+
 func (b *Bot) Start(ctx context.Context) error {
 	b.logger.Info("Bot started", "username", b.api.Self.UserName)
 
@@ -65,12 +83,13 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 	if err != nil {
 		// Create new user
 		user = &models.User{
-			ID:        message.From.ID,
-			Username:  &message.From.UserName,
-			FirstName: message.From.FirstName,
-			LastName:  &message.From.LastName,
-			Language:  models.LanguageEnglish,
-			Timezone:  "UTC",
+			ID:            message.From.ID,
+			Username:      &message.From.UserName,
+			FirstName:     message.From.FirstName,
+			LastName:      &message.From.LastName,
+			Language:      models.LanguageEnglish,
+			Timezone:      "UTC",
+			MisfirePolicy: models.MisfireFireNow,
 		}
 		if err := b.userRepo.Create(user); err != nil {
 			b.logger.Error("Failed to create user", "error", err, "user_id", message.From.ID)
@@ -96,8 +115,40 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, callbackQuery *tgbotapi.C
 		b.logger.Error("Failed to send callback answer", "error", err)
 	}
 
+	user, err := b.userRepo.GetByID(callbackQuery.From.ID)
+	if err != nil {
+		user = models.NewUser(callbackQuery.From.ID, callbackQuery.From.FirstName)
+	}
+
+	chatID := callbackQuery.From.ID
+	if callbackQuery.Message != nil {
+		chatID = callbackQuery.Message.Chat.ID
+	}
+
 	// Handle different callback types
-	// TODO: Implement callback handling logic
+	action, arg, _ := strings.Cut(callbackQuery.Data, "_")
+	switch action {
+	case "retry":
+		b.handleRetryCallback(ctx, chatID, user, arg)
+	case "preview":
+		if messageID, err := uuid.Parse(arg); err == nil {
+			b.sendPreview(ctx, chatID, user, messageID, defaultPreviewCount)
+		}
+	case "notify":
+		b.sendMessage(chatID, b.getText("remind_help", user.Language), nil)
+	case "sendnow":
+		b.handleSendNowCallback(ctx, chatID, user, arg)
+	case "postpone":
+		b.handlePostponeCallback(ctx, chatID, user, arg)
+	case "cancel":
+		b.handleCancelCallback(ctx, chatID, user, arg)
+	case "cancelseries":
+		b.handleCancelSeriesCallback(ctx, chatID, user, arg)
+	case "snooze":
+		b.handleSnoozeCallback(ctx, chatID, user, arg)
+	default:
+		b.logger.Info("Unhandled callback query", "data", callbackQuery.Data)
+	}
 }
 
 func (b *Bot) sendMessage(chatID int64, text string, keyboard interface{}) {
@@ -110,7 +161,13 @@ func (b *Bot) sendMessage(chatID int64, text string, keyboard interface{}) {
 		msg.ReplyMarkup = k
 	}
 
-	if _, err := b.api.Send(msg); err != nil {
+	var err error
+	if b.sender != nil {
+		_, err = b.sender.SendInteractive(context.Background(), msg)
+	} else {
+		_, err = b.api.Send(msg)
+	}
+	if err != nil {
 		b.logger.Error("Failed to send message", "error", err, "chat_id", chatID)
 	}
 }
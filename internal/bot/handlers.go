@@ -2,16 +2,24 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/google/uuid"
 
+	"github.com/MostafaSensei106/Riko-Chan/internal/calendar"
 	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+	"github.com/MostafaSensei106/Riko-Chan/internal/services"
 	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
 )
 
+// defaultPreviewCount is how many occurrences /preview lists by default
+const defaultPreviewCount = 5
+
 func (b *Bot) handleCommand(ctx context.Context, message *tgbotapi.Message, user *models.User) {
 	command := message.Command()
 	args := message.CommandArguments()
@@ -25,8 +33,36 @@ func (b *Bot) handleCommand(ctx context.Context, message *tgbotapi.Message, user
 		b.handleListCommand(ctx, message, user)
 	case "cancel":
 		b.handleCancelCommand(ctx, message, user, args)
+	case "edit":
+		b.handleEditCommand(ctx, message, user, args)
 	case "delete":
 		b.handleDeleteCommand(ctx, message, user, args)
+	case "misfire":
+		b.handleMisfireCommand(ctx, message, user, args)
+	case "workday":
+		b.handleWorkdayCommand(ctx, message, user, args)
+	case "weekend":
+		b.handleWeekendCommand(ctx, message, user, args)
+	case "holidays":
+		b.handleHolidaysCommand(ctx, message, user, args)
+	case "pause":
+		b.handlePauseCommand(ctx, message, user, args)
+	case "resume":
+		b.handleResumeCommand(ctx, message, user, args)
+	case "vacation":
+		b.handleVacationCommand(ctx, message, user, args)
+	case "preview":
+		b.handlePreviewCommand(ctx, message, user, args)
+	case "remind":
+		b.handleRemindCommand(ctx, message, user, args)
+	case "snooze":
+		b.handleSnoozeCommand(ctx, message, user, args)
+	case "selfdestruct":
+		b.handleSelfDestructCommand(ctx, message, user, args)
+	case "window":
+		b.handleWindowCommand(ctx, message, user, args)
+	case "webhook":
+		b.handleWebhookCommand(ctx, message, user, args)
 	case "settings":
 		b.handleSettingsCommand(ctx, message, user)
 	case "help":
@@ -60,19 +96,61 @@ func (b *Bot) handleNewCommand(ctx context.Context, message *tgbotapi.Message, u
 		return
 	}
 
-	// Parse message and time
+	// A trailing `every "<cron>"`, "every workday", "every 3 hours" or RRULE
+	// line makes the message recurring
+	args, rruleText := splitRRule(args)
+	args, cronExpr := splitCron(args)
+	args, workdays := splitWorkdays(args)
+	args, interval := splitInterval(args)
+	if interval != nil && !interval.valid() {
+		b.sendMessage(message.Chat.ID, b.getText("invalid_interval", user.Language), nil)
+		return
+	}
+
+	// "between 09:00 and 11:00" delivers at a random time in that window
+	args, window := splitWindow(args)
+	var windowLength time.Duration
+	if window != nil {
+		var ok bool
+		if windowLength, ok = window.length(); !ok {
+			b.sendMessage(message.Chat.ID, b.getText("invalid_time_format", user.Language), nil)
+			return
+		}
+	}
+	var schedule *utils.CronSchedule
+	if cronExpr != "" {
+		var err error
+		if schedule, err = utils.ParseCron(cronExpr); err != nil {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("invalid_cron", user.Language), err), nil)
+			return
+		}
+	}
+
+	// Parse message and time; with a cron expression or an interval the time
+	// is optional
 	parts := strings.SplitN(args, " at ", 2)
 	if len(parts) != 2 {
 		parts = strings.SplitN(args, " في ", 2) // Arabic support
 	}
+	if window != nil {
+		// The window sets the time of day; an at clause may only name the day
+		if len(parts) == 2 {
+			parts[1] = strings.TrimSpace(parts[1]) + " " + window.from
+		} else {
+			parts = []string{args, window.from}
+		}
+	}
 
-	if len(parts) != 2 {
+	if len(parts) != 2 && schedule == nil && interval == nil && !strings.Contains(strings.ToUpper(rruleText), "DTSTART") {
 		b.sendMessage(message.Chat.ID, b.getText("invalid_format", user.Language), nil)
 		return
 	}
 
 	content := strings.TrimSpace(parts[0])
-	timeStr := strings.TrimSpace(parts[1])
+	if content == "" {
+		b.sendMessage(message.Chat.ID, b.getText("invalid_format", user.Language), nil)
+		return
+	}
 
 	// Parse time
 	timeParser, err := utils.NewTimeParser(user.Timezone)
@@ -81,10 +159,64 @@ func (b *Bot) handleNewCommand(ctx context.Context, message *tgbotapi.Message, u
 		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
 		return
 	}
+	timeParser.SetLanguage(string(user.Language))
 
-	scheduledTime, err := timeParser.ParseRelativeTime(timeStr)
-	if err != nil {
-		b.sendMessage(message.Chat.ID, b.getText("invalid_time_format", user.Language), nil)
+	scheduledTime := timeParser.Now()
+	if len(parts) == 2 {
+		scheduledTime, err = timeParser.ParseRelativeTime(strings.TrimSpace(parts[1]))
+		if errors.Is(err, utils.ErrTimeInPast) {
+			b.sendMessage(message.Chat.ID, b.getText("time_in_past", user.Language), nil)
+			return
+		}
+		if err != nil && window != nil {
+			b.sendMessage(message.Chat.ID, b.getText("window_needs_day", user.Language), nil)
+			return
+		}
+		if err != nil {
+			b.sendMessage(message.Chat.ID, b.getText("invalid_time_format", user.Language), nil)
+			return
+		}
+	}
+	if schedule != nil {
+		// The given time itself is the first occurrence when it matches
+		scheduledTime = schedule.Next(scheduledTime.Add(-time.Second))
+	}
+	if interval != nil {
+		switch {
+		case len(interval.weekdays) > 1 && rruleText == "":
+			// Several weekdays need a weekly RRULE
+			rruleText = interval.rrule()
+		case len(interval.weekdays) == 1:
+			scheduledTime = onWeekday(scheduledTime, interval.weekdays[0])
+			// Without a time, today's weekday means the same day next week
+			if !scheduledTime.After(timeParser.Now()) {
+				scheduledTime = onWeekday(scheduledTime.AddDate(0, 0, 1), interval.weekdays[0])
+			}
+		case len(parts) != 2:
+			// Without a time the first occurrence is one interval from now
+			scheduledTime = utils.NextRecurrence(scheduledTime, string(interval.unit), interval.every, scheduledTime, scheduledTime.Location())
+		}
+	}
+
+	var recurrence *utils.Recurrence
+	if rruleText != "" {
+		if recurrence, err = utils.ParseRecurrence(rruleText, scheduledTime, scheduledTime.Location()); err != nil {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("invalid_rrule", user.Language), err), nil)
+			return
+		}
+		// A DTSTART in the past starts the series at its next occurrence
+		from := recurrence.Start.Add(-time.Second)
+		if now := timeParser.Now(); from.Before(now) {
+			from = now
+		}
+		scheduledTime = recurrence.Next(from, scheduledTime.Location())
+		if scheduledTime.IsZero() {
+			b.sendMessage(message.Chat.ID, b.getText("rrule_no_occurrences", user.Language), nil)
+			return
+		}
+	}
+	if !scheduledTime.After(timeParser.Now()) {
+		b.sendMessage(message.Chat.ID, b.getText("time_in_past", user.Language), nil)
 		return
 	}
 
@@ -92,6 +224,25 @@ func (b *Bot) handleNewCommand(ctx context.Context, message *tgbotapi.Message, u
 	msg := models.NewMessage(user.ID, models.MessageTypeText, content)
 	msg.ScheduledTime = scheduledTime
 	msg.RecipientID = &user.ID // Send to self by default
+	if window != nil {
+		msg.DeliveryWindow = &windowLength
+	}
+	if interval != nil {
+		msg.RecurrenceType = interval.unit
+		msg.RecurrenceInterval = interval.every
+	}
+	if workdays {
+		msg.RecurrenceType = models.RecurrenceWorkdays
+	}
+	if schedule != nil {
+		msg.RecurrenceType = models.RecurrenceCron
+		msg.CronExpression = &cronExpr
+	}
+	if recurrence != nil {
+		rule := recurrence.String()
+		msg.RecurrenceType = models.RecurrenceRRule
+		msg.RecurrenceRule = &rule
+	}
 
 	if err := b.messageService.CreateMessage(ctx, msg); err != nil {
 		b.logger.Error("Failed to create message", "error", err, "user_id", user.ID)
@@ -99,8 +250,9 @@ func (b *Bot) handleNewCommand(ctx context.Context, message *tgbotapi.Message, u
 		return
 	}
 
+	// The first occurrence may have moved onto a working day
 	confirmText := fmt.Sprintf(b.getText("message_scheduled", user.Language),
-		scheduledTime.Format("2006-01-02 15:04"), msg.ID)
+		formatWindow(msg.ScheduledTime.In(scheduledTime.Location()), windowLength, "2006-01-02 15:04"), msg.ID)
 
 	// Add inline keyboard for message options
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -110,6 +262,7 @@ func (b *Bot) handleNewCommand(ctx context.Context, message *tgbotapi.Message, u
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData(b.getText("send_to_other", user.Language), "recipient_"+msg.ID.String()),
+			tgbotapi.NewInlineKeyboardButtonData(b.getText("preview_schedule", user.Language), "preview_"+msg.ID.String()),
 		),
 	)
 
@@ -133,39 +286,129 @@ func (b *Bot) handleListCommand(ctx context.Context, message *tgbotapi.Message,
 	responseText.WriteString(b.getText("your_pending_messages", user.Language) + "\n\n")
 
 	for i, msg := range messages {
-		timeStr := msg.ScheduledTime.Format("2006-01-02 15:04")
-		preview := msg.Content
-		if len(preview) > 50 {
-			preview = preview[:50] + "..."
+		// Windowed messages show the window, not the drawn time
+		var window time.Duration
+		if msg.DeliveryWindow != nil {
+			window = *msg.DeliveryWindow
+		}
+		timeStr := formatWindow(msg.ScheduledTime, window, "2006-01-02 15:04")
+		preview := utils.Truncate(msg.Content, 50)
+
+		// Occurrences of a series keep the series' short ID
+		shortID := msg.ID.String()[:8]
+		if msg.SeriesID != nil {
+			shortID = msg.SeriesID.String()[:8] + " 🔁"
 		}
 
 		responseText.WriteString(fmt.Sprintf("%d. %s\n📅 %s\n💬 %s\n🆔 %s\n\n",
-			i+1, b.getText("message", user.Language), timeStr, preview, msg.ID.String()[:8]))
+			i+1, b.getText("message", user.Language), timeStr, preview, shortID))
 	}
 
 	b.sendMessage(message.Chat.ID, responseText.String(), nil)
 }
 
 func (b *Bot) handleCancelCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
-	if args == "" {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || len(fields) > 2 {
 		b.sendMessage(message.Chat.ID, b.getText("cancel_help", user.Language), nil)
 		return
 	}
 
+	var scope services.SeriesScope
+	if len(fields) == 2 {
+		var ok bool
+		if scope, ok = services.ParseSeriesScope(strings.ToLower(fields[1])); !ok {
+			b.sendMessage(message.Chat.ID, b.getText("cancel_help", user.Language), nil)
+			return
+		}
+	}
+
 	// Try to parse UUID from args (could be short form)
-	messageID, err := b.findMessageByShortID(ctx, user.ID, args)
+	messageID, err := b.findMessageByShortID(ctx, user.ID, fields[0])
 	if err != nil {
 		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
 		return
 	}
 
-	if err := b.messageService.CancelMessage(ctx, messageID, user.ID); err != nil {
+	b.cancelMessage(ctx, message.Chat.ID, user, messageID, scope)
+}
+
+// cancelMessage cancels a message. Without a scope, an occurrence of a
+// series asks which occurrences to cancel instead.
+func (b *Bot) cancelMessage(ctx context.Context, chatID int64, user *models.User, messageID uuid.UUID, scope services.SeriesScope) {
+	msg, err := b.messageService.GetMessage(ctx, messageID)
+	if err != nil || msg.UserID != user.ID {
+		b.sendMessage(chatID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	inSeries := services.InSeries(msg)
+	if inSeries && scope == "" {
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.getText("scope_this", user.Language), "cancelseries_this_"+messageID.String()),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.getText("scope_following", user.Language), "cancelseries_following_"+messageID.String()),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(b.getText("scope_all", user.Language), "cancelseries_all_"+messageID.String()),
+			),
+		)
+		b.sendMessage(chatID, b.getText("series_cancel_prompt", user.Language), &keyboard)
+		return
+	}
+
+	if err := b.messageService.CancelOccurrences(ctx, messageID, user.ID, scope); err != nil {
+		if errors.Is(err, services.ErrMessageNotPending) {
+			b.sendMessage(chatID, b.getText("message_not_pending", user.Language), nil)
+			return
+		}
 		b.logger.Error("Failed to cancel message", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(chatID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	if inSeries {
+		b.sendMessage(chatID, b.getText("series_cancelled_"+string(scope), user.Language), nil)
+		return
+	}
+	b.sendMessage(chatID, b.getText("message_cancelled", user.Language), nil)
+}
+
+func (b *Bot) handleEditCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	shortID, content, _ := strings.Cut(strings.TrimSpace(args), " ")
+	content = strings.TrimSpace(content)
+	if shortID == "" || content == "" {
+		b.sendMessage(message.Chat.ID, b.getText("edit_help", user.Language), nil)
+		return
+	}
+
+	// An optional leading scope applies the edit to more of a series
+	scope := services.ScopeOccurrence
+	if word, rest, ok := strings.Cut(content, " "); ok {
+		if parsed, ok := services.ParseSeriesScope(strings.ToLower(word)); ok {
+			scope, content = parsed, strings.TrimSpace(rest)
+		}
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, shortID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	if err := b.messageService.EditMessage(ctx, messageID, user.ID, scope, content); err != nil {
+		if errors.Is(err, services.ErrMessageNotPending) {
+			b.sendMessage(message.Chat.ID, b.getText("message_not_pending", user.Language), nil)
+			return
+		}
+		b.logger.Error("Failed to edit message", "error", err, "user_id", user.ID, "message_id", messageID)
 		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
 		return
 	}
 
-	b.sendMessage(message.Chat.ID, b.getText("message_cancelled", user.Language), nil)
+	b.sendMessage(message.Chat.ID, b.getText("message_edited", user.Language), nil)
 }
 
 func (b *Bot) handleDeleteCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
@@ -189,6 +432,588 @@ func (b *Bot) handleDeleteCommand(ctx context.Context, message *tgbotapi.Message
 	b.sendMessage(message.Chat.ID, b.getText("message_deleted", user.Language), nil)
 }
 
+func (b *Bot) handleMisfireCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || len(fields) > 2 {
+		b.sendMessage(message.Chat.ID, b.getText("misfire_help", user.Language), nil)
+		return
+	}
+
+	policy := models.MisfirePolicy(strings.ToLower(fields[0]))
+	if !policy.IsValid() {
+		b.sendMessage(message.Chat.ID, b.getText("misfire_help", user.Language), nil)
+		return
+	}
+
+	// Without a message ID the policy becomes the user's default
+	if len(fields) == 1 {
+		user.MisfirePolicy = policy
+		if err := b.userRepo.Update(user); err != nil {
+			b.logger.Error("Failed to update user", "error", err, "user_id", user.ID)
+			b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+			return
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("misfire_default_updated", user.Language), policy), nil)
+		return
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, fields[1])
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	if err := b.messageService.SetMisfirePolicy(ctx, messageID, user.ID, &policy); err != nil {
+		b.logger.Error("Failed to set misfire policy", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("misfire_updated", user.Language), policy), nil)
+}
+
+func (b *Bot) handleWorkdayCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		b.sendMessage(message.Chat.ID, b.getText("workday_help", user.Language), nil)
+		return
+	}
+
+	var policy *models.WorkdayPolicy
+	if option := strings.ToLower(fields[0]); option != "off" {
+		p := models.WorkdayPolicy(option)
+		if !p.IsValid() {
+			b.sendMessage(message.Chat.ID, b.getText("workday_help", user.Language), nil)
+			return
+		}
+		policy = &p
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, fields[1])
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	msg, err := b.messageService.SetWorkdayPolicy(ctx, messageID, user.ID, policy)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMessageNotPending):
+			b.sendMessage(message.Chat.ID, b.getText("message_not_pending", user.Language), nil)
+		case errors.Is(err, services.ErrSkipNeedsRecurrence):
+			b.sendMessage(message.Chat.ID, b.getText("workday_skip_one_off", user.Language), nil)
+		default:
+			b.logger.Error("Failed to set workday policy", "error", err, "user_id", user.ID, "message_id", messageID)
+			b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		}
+		return
+	}
+
+	if policy == nil {
+		b.sendMessage(message.Chat.ID, b.getText("workday_off", user.Language), nil)
+		return
+	}
+
+	scheduledTime := msg.ScheduledTime
+	if loc, err := time.LoadLocation(user.Timezone); err == nil {
+		scheduledTime = scheduledTime.In(loc)
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("workday_set", user.Language), *policy, scheduledTime.Format("2006-01-02 15:04")), nil)
+}
+
+func (b *Bot) handleWeekendCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		b.sendMessage(message.Chat.ID, b.getText("weekend_help", user.Language), nil)
+		return
+	}
+
+	weekend, err := calendar.ParseWeekend(strings.ReplaceAll(args, " ", ""))
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("weekend_help", user.Language), nil)
+		return
+	}
+
+	value := weekend.String()
+	user.Weekend = &value
+	if err := b.userRepo.Update(user); err != nil {
+		b.logger.Error("Failed to update user", "error", err, "user_id", user.ID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("weekend_set", user.Language), value), nil)
+}
+
+func (b *Bot) handleHolidaysCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	name := strings.ToLower(strings.TrimSpace(args))
+	if name == "" {
+		available := strings.Join(b.messageService.HolidayCalendars(), ", ")
+		if available == "" {
+			available = "-"
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("holidays_help", user.Language), available), nil)
+		return
+	}
+
+	if name == "off" {
+		user.HolidayCalendar = nil
+	} else {
+		if !b.messageService.HasHolidayCalendar(name) {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("holidays_unknown", user.Language), name), nil)
+			return
+		}
+		user.HolidayCalendar = &name
+	}
+
+	if err := b.userRepo.Update(user); err != nil {
+		b.logger.Error("Failed to update user", "error", err, "user_id", user.ID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	if user.HolidayCalendar == nil {
+		b.sendMessage(message.Chat.ID, b.getText("holidays_off", user.Language), nil)
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("holidays_set", user.Language), name), nil)
+}
+
+func (b *Bot) handlePauseCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	if args == "" {
+		b.sendMessage(message.Chat.ID, b.getText("pause_help", user.Language), nil)
+		return
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, strings.TrimSpace(args))
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	if err := b.messageService.PauseMessage(ctx, messageID, user.ID); err != nil {
+		if errors.Is(err, services.ErrMessageNotPending) {
+			b.sendMessage(message.Chat.ID, b.getText("message_not_pending", user.Language), nil)
+			return
+		}
+		b.logger.Error("Failed to pause message", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, b.getText("message_paused", user.Language), nil)
+}
+
+func (b *Bot) handleResumeCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	if args == "" {
+		b.sendMessage(message.Chat.ID, b.getText("resume_help", user.Language), nil)
+		return
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, strings.TrimSpace(args))
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	msg, err := b.messageService.ResumeMessage(ctx, messageID, user.ID)
+	if err != nil {
+		if errors.Is(err, services.ErrMessageNotPending) {
+			b.sendMessage(message.Chat.ID, b.getText("message_not_paused", user.Language), nil)
+			return
+		}
+		b.logger.Error("Failed to resume message", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+	if msg.Status != models.MessageStatusPending {
+		b.sendMessage(message.Chat.ID, b.getText("series_ended", user.Language), nil)
+		return
+	}
+
+	scheduledTime := msg.ScheduledTime
+	if loc, err := time.LoadLocation(user.Timezone); err == nil {
+		scheduledTime = scheduledTime.In(loc)
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("message_resumed", user.Language), scheduledTime.Format("2006-01-02 15:04")), nil)
+}
+
+func (b *Bot) handleVacationCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	args = strings.TrimSpace(args)
+	if strings.EqualFold(args, "off") {
+		if err := b.messageService.ClearVacation(ctx, user.ID); err != nil {
+			b.logger.Error("Failed to clear vacation", "error", err, "user_id", user.ID)
+			b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+			return
+		}
+		b.sendMessage(message.Chat.ID, b.getText("vacation_off", user.Language), nil)
+		return
+	}
+
+	fromStr, untilStr, found := strings.Cut(args, " to ")
+	if !found {
+		fromStr, untilStr, found = strings.Cut(args, " إلى ")
+	}
+	if !found {
+		b.sendMessage(message.Chat.ID, b.getText("vacation_help", user.Language), nil)
+		return
+	}
+
+	timeParser, err := utils.NewTimeParser(user.Timezone)
+	if err != nil {
+		b.logger.Error("Failed to create time parser", "error", err, "user_id", user.ID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+	timeParser.SetLanguage(string(user.Language))
+
+	from, err := timeParser.ParseRelativeTime(fromStr)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("invalid_time_format", user.Language), nil)
+		return
+	}
+	until, err := timeParser.ParseRelativeTime(untilStr)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("invalid_time_format", user.Language), nil)
+		return
+	}
+	if !until.After(from) || !until.After(time.Now()) {
+		b.sendMessage(message.Chat.ID, b.getText("vacation_help", user.Language), nil)
+		return
+	}
+
+	if err := b.messageService.SetVacation(ctx, user.ID, from, until); err != nil {
+		b.logger.Error("Failed to set vacation", "error", err, "user_id", user.ID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("vacation_set", user.Language),
+		from.Format("2006-01-02 15:04"), until.Format("2006-01-02 15:04")), nil)
+}
+
+func (b *Bot) handlePreviewCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || len(fields) > 2 {
+		b.sendMessage(message.Chat.ID, b.getText("preview_help", user.Language), nil)
+		return
+	}
+
+	count := defaultPreviewCount
+	if len(fields) == 2 {
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n <= 0 {
+			b.sendMessage(message.Chat.ID, b.getText("preview_help", user.Language), nil)
+			return
+		}
+		count = n
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, fields[0])
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	b.sendPreview(ctx, message.Chat.ID, user, messageID, count)
+}
+
+func (b *Bot) sendPreview(ctx context.Context, chatID int64, user *models.User, messageID uuid.UUID, count int) {
+	occurrences, err := b.messageService.PreviewOccurrences(ctx, messageID, user.ID, count)
+	if err != nil {
+		if errors.Is(err, services.ErrMessageNotPending) {
+			b.sendMessage(chatID, b.getText("message_not_pending", user.Language), nil)
+			return
+		}
+		b.logger.Error("Failed to preview message", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(chatID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+	if len(occurrences) == 0 {
+		b.sendMessage(chatID, b.getText("no_upcoming_occurrences", user.Language), nil)
+		return
+	}
+
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var responseText strings.Builder
+	responseText.WriteString(fmt.Sprintf(b.getText("upcoming_occurrences", user.Language), user.Timezone) + "\n\n")
+	for i, occurrence := range occurrences {
+		responseText.WriteString(fmt.Sprintf("%d. %s\n", i+1, formatWindow(occurrence.At.In(loc), occurrence.Window, "Mon 2006-01-02 15:04")))
+		if len(occurrence.Reminders) > 0 {
+			reminders := make([]string, len(occurrence.Reminders))
+			for j, reminder := range occurrence.Reminders {
+				reminders[j] = reminder.In(loc).Format("01-02 15:04")
+			}
+			responseText.WriteString("   🔔 " + strings.Join(reminders, ", ") + "\n")
+		}
+	}
+
+	b.sendMessage(chatID, responseText.String(), nil)
+}
+
+func (b *Bot) handleRemindCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	shortID, offsets, found := strings.Cut(strings.TrimSpace(args), " ")
+	if !found || strings.TrimSpace(offsets) == "" {
+		b.sendMessage(message.Chat.ID, b.getText("remind_help", user.Language), nil)
+		return
+	}
+
+	// "off" removes every reminder; otherwise offsets are comma separated
+	var reminders models.Reminders
+	if !strings.EqualFold(strings.TrimSpace(offsets), "off") {
+		for _, offset := range strings.Split(offsets, ",") {
+			d, err := utils.ParseDuration(offset)
+			if err != nil || d <= 0 {
+				b.sendMessage(message.Chat.ID, b.getText("remind_help", user.Language), nil)
+				return
+			}
+			if !reminders.Contains(d) {
+				reminders = append(reminders, d)
+			}
+		}
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, shortID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	if err := b.messageService.SetReminders(ctx, messageID, user.ID, reminders); err != nil {
+		b.logger.Error("Failed to set reminders", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	if len(reminders) == 0 {
+		b.sendMessage(message.Chat.ID, b.getText("reminders_off", user.Language), nil)
+		return
+	}
+
+	labels := make([]string, len(reminders))
+	for i, reminder := range reminders {
+		labels[i] = reminder.String()
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("reminders_set", user.Language), strings.Join(labels, ", ")), nil)
+}
+
+func (b *Bot) handleSnoozeCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	shortID, timeStr, found := strings.Cut(strings.TrimSpace(args), " ")
+	if !found || strings.TrimSpace(timeStr) == "" {
+		b.sendMessage(message.Chat.ID, b.getText("snooze_help", user.Language), nil)
+		return
+	}
+
+	timeParser, err := utils.NewTimeParser(user.Timezone)
+	if err != nil {
+		b.logger.Error("Failed to create time parser", "error", err, "user_id", user.ID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+	timeParser.SetLanguage(string(user.Language))
+
+	until, err := timeParser.ParseRelativeTime(timeStr)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("invalid_time_format", user.Language), nil)
+		return
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, shortID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	b.snoozeMessage(ctx, message.Chat.ID, user, messageID, until)
+}
+
+func (b *Bot) handleWindowCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	shortID, length, found := strings.Cut(strings.TrimSpace(args), " ")
+	if !found || strings.TrimSpace(length) == "" {
+		b.sendMessage(message.Chat.ID, b.getText("window_help", user.Language), nil)
+		return
+	}
+
+	// "off" delivers the message at its scheduled time again
+	var window *time.Duration
+	if !strings.EqualFold(strings.TrimSpace(length), "off") {
+		d, err := utils.ParseDuration(length)
+		if err != nil || d <= 0 {
+			b.sendMessage(message.Chat.ID, b.getText("window_help", user.Language), nil)
+			return
+		}
+		window = &d
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, shortID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	if err := b.messageService.SetDeliveryWindow(ctx, messageID, user.ID, window); err != nil {
+		switch {
+		case errors.Is(err, services.ErrWindowTooLong):
+			b.sendMessage(message.Chat.ID, b.getText("window_help", user.Language), nil)
+		case errors.Is(err, services.ErrMessageNotPending):
+			b.sendMessage(message.Chat.ID, b.getText("message_not_pending", user.Language), nil)
+		default:
+			b.logger.Error("Failed to set delivery window", "error", err, "user_id", user.ID, "message_id", messageID)
+			b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		}
+		return
+	}
+
+	if window == nil {
+		b.sendMessage(message.Chat.ID, b.getText("window_off", user.Language), nil)
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("window_set", user.Language), window.String()), nil)
+}
+
+func (b *Bot) handleSelfDestructCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	shortID, delay, found := strings.Cut(strings.TrimSpace(args), " ")
+	if !found || strings.TrimSpace(delay) == "" {
+		b.sendMessage(message.Chat.ID, b.getText("selfdestruct_help", user.Language), nil)
+		return
+	}
+
+	// "off" keeps the delivered message in the chat
+	var after *time.Duration
+	if !strings.EqualFold(strings.TrimSpace(delay), "off") {
+		d, err := utils.ParseDuration(delay)
+		if err != nil || d <= 0 {
+			b.sendMessage(message.Chat.ID, b.getText("selfdestruct_help", user.Language), nil)
+			return
+		}
+		after = &d
+	}
+
+	messageID, err := b.findMessageByShortID(ctx, user.ID, shortID)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	if err := b.messageService.SetSelfDestruct(ctx, messageID, user.ID, after); err != nil {
+		b.logger.Error("Failed to set self-destruct", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	if after == nil {
+		b.sendMessage(message.Chat.ID, b.getText("selfdestruct_off", user.Language), nil)
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("selfdestruct_set", user.Language), after.String()), nil)
+}
+
+func (b *Bot) handleWebhookCommand(ctx context.Context, message *tgbotapi.Message, user *models.User, args string) {
+	if b.webhookService == nil {
+		b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	action, arg, _ := strings.Cut(strings.TrimSpace(args), " ")
+	arg = strings.TrimSpace(arg)
+
+	switch strings.ToLower(action) {
+	case "add":
+		if arg == "" {
+			b.sendMessage(message.Chat.ID, b.getText("webhook_help", user.Language), nil)
+			return
+		}
+		webhook, err := b.webhookService.AddWebhook(ctx, user.ID, arg)
+		if err != nil {
+			b.logger.Error("Failed to add webhook", "error", err, "user_id", user.ID)
+			b.sendMessage(message.Chat.ID, b.getText("webhook_invalid", user.Language), nil)
+			return
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf(b.getText("webhook_added", user.Language),
+			webhook.URL, webhook.ID.String()[:8], webhook.Secret), nil)
+	case "list":
+		webhooks, err := b.webhookService.ListWebhooks(ctx, user.ID)
+		if err != nil {
+			b.logger.Error("Failed to list webhooks", "error", err, "user_id", user.ID)
+			b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+			return
+		}
+		if len(webhooks) == 0 {
+			b.sendMessage(message.Chat.ID, b.getText("no_webhooks", user.Language), nil)
+			return
+		}
+
+		var responseText strings.Builder
+		responseText.WriteString(b.getText("your_webhooks", user.Language) + "\n\n")
+		for _, webhook := range webhooks {
+			responseText.WriteString(fmt.Sprintf("🔗 %s\n🆔 %s\n\n", webhook.URL, webhook.ID.String()[:8]))
+		}
+		b.sendMessage(message.Chat.ID, responseText.String(), nil)
+	case "remove":
+		webhooks, err := b.webhookService.ListWebhooks(ctx, user.ID)
+		if err != nil || arg == "" {
+			b.sendMessage(message.Chat.ID, b.getText("webhook_help", user.Language), nil)
+			return
+		}
+		for _, webhook := range webhooks {
+			if !strings.HasPrefix(webhook.ID.String(), arg) {
+				continue
+			}
+			if err := b.webhookService.RemoveWebhook(ctx, webhook.ID, user.ID); err != nil {
+				b.logger.Error("Failed to remove webhook", "error", err, "user_id", user.ID, "webhook_id", webhook.ID)
+				b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+				return
+			}
+			b.sendMessage(message.Chat.ID, b.getText("webhook_removed", user.Language), nil)
+			return
+		}
+		b.sendMessage(message.Chat.ID, b.getText("webhook_not_found", user.Language), nil)
+	case "log":
+		deliveries, err := b.webhookService.RecentDeliveries(ctx, user.ID, 10)
+		if err != nil {
+			b.logger.Error("Failed to get webhook deliveries", "error", err, "user_id", user.ID)
+			b.sendMessage(message.Chat.ID, b.getText("error_occurred", user.Language), nil)
+			return
+		}
+		if len(deliveries) == 0 {
+			b.sendMessage(message.Chat.ID, b.getText("no_webhook_deliveries", user.Language), nil)
+			return
+		}
+
+		loc, err := time.LoadLocation(user.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		var responseText strings.Builder
+		responseText.WriteString(b.getText("webhook_log", user.Language) + "\n\n")
+		for _, delivery := range deliveries {
+			status := "⏳"
+			if delivery.DeliveredAt != nil {
+				status = "✅"
+			} else if delivery.LastError != nil {
+				status = "❌"
+			}
+			responseText.WriteString(fmt.Sprintf("%s %s %s (%d)", status,
+				delivery.CreatedAt.In(loc).Format("2006-01-02 15:04"), delivery.EventType, delivery.Attempts))
+			if delivery.StatusCode != nil {
+				responseText.WriteString(fmt.Sprintf(" HTTP %d", *delivery.StatusCode))
+			}
+			if delivery.DeliveredAt == nil && delivery.LastError != nil {
+				responseText.WriteString("\n   " + *delivery.LastError)
+			}
+			responseText.WriteString("\n")
+		}
+		b.sendMessage(message.Chat.ID, responseText.String(), nil)
+	default:
+		b.sendMessage(message.Chat.ID, b.getText("webhook_help", user.Language), nil)
+	}
+}
+
 func (b *Bot) handleSettingsCommand(ctx context.Context, message *tgbotapi.Message, user *models.User) {
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
@@ -233,17 +1058,132 @@ func (b *Bot) handleTextMessage(ctx context.Context, message *tgbotapi.Message,
 	}
 }
 
-func (b *Bot) findMessageByShortID(ctx context.Context, userID int64, shortID string) (uuid.UUID, error) {
-	messages, err := b.messageService.GetUserMessages(ctx, userID, "", 50, 0)
+func (b *Bot) handleRetryCallback(ctx context.Context, chatID int64, user *models.User, arg string) {
+	messageID, err := uuid.Parse(arg)
+	if err != nil {
+		b.sendMessage(chatID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	if err := b.messageService.RetryFailedMessage(ctx, messageID, user.ID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrMessageNotFound):
+			b.sendMessage(chatID, b.getText("message_not_found", user.Language), nil)
+		case errors.Is(err, services.ErrMessageNotFailed):
+			b.sendMessage(chatID, b.getText("message_not_failed", user.Language), nil)
+		default:
+			b.logger.Error("Failed to retry message", "error", err, "user_id", user.ID, "message_id", messageID)
+			b.sendMessage(chatID, b.getText("error_occurred", user.Language), nil)
+		}
+		return
+	}
+
+	b.sendMessage(chatID, b.getText("message_retry_scheduled", user.Language), nil)
+}
+
+func (b *Bot) handleSendNowCallback(ctx context.Context, chatID int64, user *models.User, arg string) {
+	messageID, err := uuid.Parse(arg)
 	if err != nil {
-		return uuid.Nil, err
+		b.sendMessage(chatID, b.getText("message_not_found", user.Language), nil)
+		return
 	}
 
-	for _, msg := range messages {
-		if strings.HasPrefix(msg.ID.String(), shortID) {
-			return msg.ID, nil
+	if err := b.messageService.SendMessageNow(ctx, messageID, user.ID); err != nil {
+		if errors.Is(err, services.ErrMessageNotPending) || errors.Is(err, services.ErrMessageBusy) {
+			b.sendMessage(chatID, b.getText("message_not_pending", user.Language), nil)
+			return
 		}
+		b.logger.Error("Failed to send message now", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(chatID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	b.sendMessage(chatID, b.getText("message_sent_now", user.Language), nil)
+}
+
+func (b *Bot) handlePostponeCallback(ctx context.Context, chatID int64, user *models.User, arg string) {
+	messageID, err := uuid.Parse(arg)
+	if err != nil {
+		b.sendMessage(chatID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	scheduledTime, err := b.messageService.PostponeMessage(ctx, messageID, user.ID, services.ReminderPostpone)
+	if err != nil {
+		if errors.Is(err, services.ErrMessageNotPending) {
+			b.sendMessage(chatID, b.getText("message_not_pending", user.Language), nil)
+			return
+		}
+		b.logger.Error("Failed to postpone message", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(chatID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	if loc, err := time.LoadLocation(user.Timezone); err == nil {
+		scheduledTime = scheduledTime.In(loc)
+	}
+	b.sendMessage(chatID, fmt.Sprintf(b.getText("message_postponed", user.Language), scheduledTime.Format("2006-01-02 15:04")), nil)
+}
+
+func (b *Bot) handleCancelCallback(ctx context.Context, chatID int64, user *models.User, arg string) {
+	messageID, err := uuid.Parse(arg)
+	if err != nil {
+		b.sendMessage(chatID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	b.cancelMessage(ctx, chatID, user, messageID, "")
+}
+
+func (b *Bot) handleCancelSeriesCallback(ctx context.Context, chatID int64, user *models.User, arg string) {
+	option, id, _ := strings.Cut(arg, "_")
+	scope, ok := services.ParseSeriesScope(option)
+	messageID, err := uuid.Parse(id)
+	if !ok || err != nil {
+		b.sendMessage(chatID, b.getText("message_not_found", user.Language), nil)
+		return
 	}
 
-	return uuid.Nil, fmt.Errorf("message not found")
+	b.cancelMessage(ctx, chatID, user, messageID, scope)
+}
+
+func (b *Bot) handleSnoozeCallback(ctx context.Context, chatID int64, user *models.User, arg string) {
+	option, id, _ := strings.Cut(arg, "_")
+	messageID, err := uuid.Parse(id)
+	if err != nil {
+		b.sendMessage(chatID, b.getText("message_not_found", user.Language), nil)
+		return
+	}
+
+	// A custom time is typed in as a /snooze command
+	if option == services.SnoozeCustom {
+		shortID := messageID.String()[:8]
+		b.sendMessage(chatID, fmt.Sprintf(b.getText("snooze_custom_prompt", user.Language), shortID, shortID), nil)
+		return
+	}
+
+	until, err := services.SnoozeUntil(option, user.Timezone, time.Now())
+	if err != nil {
+		b.logger.Info("Unhandled snooze option", "option", option)
+		return
+	}
+
+	b.snoozeMessage(ctx, chatID, user, messageID, until)
+}
+
+func (b *Bot) snoozeMessage(ctx context.Context, chatID int64, user *models.User, messageID uuid.UUID, until time.Time) {
+	if _, err := b.messageService.SnoozeMessage(ctx, messageID, user.ID, until); err != nil {
+		b.logger.Error("Failed to snooze message", "error", err, "user_id", user.ID, "message_id", messageID)
+		b.sendMessage(chatID, b.getText("error_occurred", user.Language), nil)
+		return
+	}
+
+	if loc, err := time.LoadLocation(user.Timezone); err == nil {
+		until = until.In(loc)
+	}
+	b.sendMessage(chatID, fmt.Sprintf(b.getText("message_snoozed", user.Language), until.Format("2006-01-02 15:04")), nil)
+}
+
+func (b *Bot) findMessageByShortID(ctx context.Context, userID int64, shortID string) (uuid.UUID, error) {
+	return b.messageService.FindMessageByShortID(ctx, userID, shortID)
 }
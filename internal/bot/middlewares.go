@@ -6,68 +6,202 @@ import "github.com/MostafaSensei106/Riko-Chan/internal/models"
 func (b *Bot) getText(key string, language models.UserLanguage) string {
 	texts := map[models.UserLanguage]map[string]string{
 		models.LanguageEnglish: {
-			"welcome":               "🌟 Welcome to Future Message Bot! 🌟\n\nI help you schedule messages to be sent in the future.",
-			"help_text":             "Use /new to create a message, /list to view pending messages, and /settings to configure your preferences.",
-			"new_message":           "📝 New Message",
-			"my_messages":           "📋 My Messages",
-			"settings":              "⚙️ Settings",
-			"help":                  "❓ Help",
-			"unknown_command":       "Unknown command. Type /help to see available commands.",
-			"new_message_help":      "Usage: /new <message> at <time>\nExample: /new Hello world at 2024-01-01 15:30",
-			"invalid_format":        "Invalid format. Use: <message> at <time>",
-			"invalid_time_format":   "Invalid time format. Examples: 'tomorrow 9:00', 'after 2 hours', '2024-01-01 15:30'",
-			"error_occurred":        "An error occurred. Please try again.",
-			"message_scheduled":     "✅ Message scheduled for %s\n🆔 ID: %s",
-			"add_notification":      "🔔 Add Notification",
-			"make_recurring":        "🔄 Make Recurring",
-			"send_to_other":         "👤 Send to Other",
-			"no_pending_messages":   "You have no pending messages.",
-			"your_pending_messages": "📋 Your Pending Messages:",
-			"message":               "Message",
-			"cancel_help":           "Usage: /cancel <message_id>",
-			"delete_help":           "Usage: /delete <message_id>",
-			"message_not_found":     "Message not found.",
-			"message_cancelled":     "Message cancelled successfully.",
-			"message_deleted":       "Message deleted successfully.",
-			"change_language":       "Change Language",
-			"change_timezone":       "Change Timezone",
-			"integrations":          "Integrations",
-			"current_settings":      "🛠 Current Settings:\n🌍 Language: %s\n🕒 Timezone: %s",
-			"detailed_help":         "🤖 Future Message Bot Help\n\n📝 Commands:\n/new <message> at <time> - Schedule a message\n/list - View pending messages\n/cancel <id> - Cancel a message\n/delete <id> - Delete a message\n/settings - Configure settings\n\n⏰ Time formats:\n- 'after 2 hours'\n- 'tomorrow 9:00'\n- '2024-01-01 15:30'\n- 'next Friday 14:00'",
-			"new_message_prompt":    "Please send your message in the format:\n<message> at <time>",
-			"unclear_message":       "I didn't understand. Use /help to see how to use me.",
+			"welcome":                    "🌟 Welcome to Future Message Bot! 🌟\n\nI help you schedule messages to be sent in the future.",
+			"help_text":                  "Use /new to create a message, /list to view pending messages, and /settings to configure your preferences.",
+			"new_message":                "📝 New Message",
+			"my_messages":                "📋 My Messages",
+			"settings":                   "⚙️ Settings",
+			"help":                       "❓ Help",
+			"unknown_command":            "Unknown command. Type /help to see available commands.",
+			"new_message_help":           "Usage: /new <message> at <time> [every \"<cron>\"]\nExample: /new Hello world at 2024-01-01 15:30\nRecurring: /new Standup every \"30 8 * * 1-5\"\nWorking days: /new Standup at 9:00 every workday\nIntervals: /new Drink water every 2 hours, /new Review at 10:00 every 2 weeks on Monday\nRandom time: /new Stretch between 09:00 and 11:00 every day",
+			"invalid_format":             "Invalid format. Use: <message> at <time>",
+			"invalid_time_format":        "Invalid time format. Examples: 'tomorrow 9:00', 'after 2 hours', '2024-01-01 15:30'",
+			"time_in_past":               "❌ That time has already passed. Please choose a time in the future.",
+			"window_needs_day":           "❌ A delivery window sets the time of day, so give only the day with it, e.g. /new Stretch at tomorrow between 09:00 and 11:00",
+			"error_occurred":             "An error occurred. Please try again.",
+			"message_scheduled":          "✅ Message scheduled for %s\n🆔 ID: %s",
+			"add_notification":           "🔔 Add Notification",
+			"make_recurring":             "🔄 Make Recurring",
+			"send_to_other":              "👤 Send to Other",
+			"no_pending_messages":        "You have no pending messages.",
+			"your_pending_messages":      "📋 Your Pending Messages:",
+			"message":                    "Message",
+			"cancel_help":                "Usage: /cancel <message_id> [this|following|all]",
+			"delete_help":                "Usage: /delete <message_id>",
+			"message_not_found":          "Message not found.",
+			"message_cancelled":          "Message cancelled successfully.",
+			"edit_help":                  "Usage: /edit <message_id> [this|following|all] <new text>\nFor a recurring series: this = this occurrence only, following = this and all later ones, all = the whole series.",
+			"message_edited":             "✅ Message updated.",
+			"series_cancel_prompt":       "🔁 This message is part of a recurring series. What do you want to cancel?",
+			"scope_this":                 "This occurrence",
+			"scope_following":            "This and following",
+			"scope_all":                  "The whole series",
+			"series_cancelled_this":      "✅ This occurrence was cancelled; the series continues.",
+			"series_cancelled_following": "✅ This and all following occurrences were cancelled.",
+			"series_cancelled_all":       "✅ The whole series was cancelled.",
+			"message_deleted":            "Message deleted successfully.",
+			"change_language":            "Change Language",
+			"change_timezone":            "Change Timezone",
+			"integrations":               "Integrations",
+			"current_settings":           "🛠 Current Settings:\n🌍 Language: %s\n🕒 Timezone: %s",
+			"detailed_help":              "🤖 Future Message Bot Help\n\n📝 Commands:\n/new <message> at <time> - Schedule a message\n/new <message> [at <time>] every \"<cron>\" - Repeat on a cron schedule\n/new <message> at <time> RRULE:<rule> [EXDATE:<times>] - Repeat on an iCalendar rule\n/new <message> at <time> every workday - Repeat on working days\n/new <message> [at <time>] every <n> <unit> [on <days>] - Repeat every few minutes, hours, days, weeks, months or years\n/list - View pending messages\n/edit <id> [this|following|all] <text> - Edit a message or series\n/cancel <id> [this|following|all] - Cancel a message or series\n/delete <id> - Delete a message\n/misfire <policy> [id] - Handle overdue messages\n/workday <skip|previous|next|off> <id> - Keep a message off weekends and holidays\n/weekend <days> - Set your weekend days\n/holidays [calendar|off] - Choose a holiday calendar\n/pause <id> - Hold a message\n/resume <id> - Resume a held message\n/vacation <from> to <until>|off - Hold all messages while away\n/preview <id> [count] - Show upcoming deliveries\n/remind <id> <offsets|off> - Remind you before a message goes out\n/snooze <id> <time> - Send a delivered message again later\n/selfdestruct <id> <duration|off> - Delete a message after it is sent\n/window <id> <duration|off> - Send at a random time within a window\n/webhook <add|list|remove|log> - Manage webhooks\n/settings - Configure settings\n\n⏰ Time formats:\n- 'after 2 hours' or 'in 2 hours'\n- 'after 1 hour 30 minutes', 'in 1h30m', 'in 1.5 hours'\n- 'in 1 month' (same day next month)\n- 'tomorrow 9:00', 'tonight', 'noon'\n- '2024-01-01 15:30'\n- 'next Friday 14:00', 'friday 2pm'\n- 'March 3rd at noon', '3 March 9am'\n- Arabic and Japanese: 'بعد ساعتين', '明日の9時', '3時間後'",
+			"new_message_prompt":         "Please send your message in the format:\n<message> at <time>",
+			"unclear_message":            "I didn't understand. Use /help to see how to use me.",
+			"message_retry_scheduled":    "🔁 Message queued for another delivery attempt.",
+			"message_not_failed":         "This message has not failed, so there is nothing to retry.",
+			"misfire_help":               "Usage: /misfire <fire_now|skip|coalesce> [message_id]\nDecides what happens to messages that are overdue after downtime:\n- fire_now: send every missed occurrence\n- skip: drop missed occurrences and wait for the next one\n- coalesce: send once, then continue with the next occurrence\nWithout a message ID it sets your default.",
+			"misfire_updated":            "✅ Misfire policy of the message set to %s.",
+			"misfire_default_updated":    "✅ Your default misfire policy is now %s.",
+			"workday_help":               "Usage: /workday <skip|previous|next|off> <message_id>\nDecides what happens when a message falls on a weekend or holiday:\n- skip: drop that occurrence (recurring messages only)\n- previous: send on the working day before\n- next: send on the working day after\n- off: send on any day",
+			"workday_set":                "✅ Working-day policy set to %s. Next delivery: %s",
+			"workday_off":                "✅ The message is no longer kept to working days.",
+			"workday_skip_one_off":       "❌ Only recurring messages can skip non-working days; use previous or next instead.",
+			"weekend_help":               "Usage: /weekend <days>\nExample: /weekend fri,sat or /weekend none",
+			"weekend_set":                "✅ Your weekend is now: %s",
+			"holidays_help":              "Usage: /holidays <calendar|off>\nAvailable calendars: %s",
+			"holidays_set":               "✅ Holiday calendar set to %s.",
+			"holidays_off":               "✅ Holiday calendar turned off.",
+			"holidays_unknown":           "❌ Unknown holiday calendar: %s",
+			"selfdestruct_help":          "Usage: /selfdestruct <message_id> <duration|off>\nDeletes the message from the chat the given time after it is sent, e.g. /selfdestruct 1a2b3c4d 30 minutes or /selfdestruct 1a2b3c4d 2h.",
+			"selfdestruct_set":           "💣 The message will delete itself %s after it is sent.",
+			"selfdestruct_off":           "✅ Self-destruct turned off for the message.",
+			"window_help":                "Usage: /window <message_id> <duration|off>\nSends the message at a random time within the given duration (up to 24 hours) after its scheduled time.\nExample: /window 1a2b3c4d 2h",
+			"window_set":                 "✅ The message will go out at a random time within %s of its scheduled time.",
+			"window_off":                 "✅ The message will go out at its scheduled time.",
+			"remind_help":                "Usage: /remind <message_id> <offsets|off>\nReminds you before the message goes out, e.g. /remind 1a2b3c4d 1 day, 1 hour, 10 minutes.",
+			"reminders_set":              "🔔 You will be reminded %s before the message goes out.",
+			"reminders_off":              "✅ Reminders turned off for the message.",
+			"message_not_pending":        "This message is no longer pending.",
+			"message_sent_now":           "📤 Message sent.",
+			"message_postponed":          "⏳ Message postponed to %s.",
+			"snooze_help":                "Usage: /snooze <message_id> <time>\nSends a delivered message to you again, e.g. /snooze 1a2b3c4d after 2 hours.",
+			"snooze_custom_prompt":       "Send /snooze %s <time>, e.g. /snooze %s tomorrow 18:00",
+			"message_snoozed":            "💤 Snoozed until %s.",
+			"pause_help":                 "Usage: /pause <message_id>",
+			"resume_help":                "Usage: /resume <message_id>",
+			"message_paused":             "⏸ Message paused. Use /resume to continue it.",
+			"message_resumed":            "▶️ Message resumed, next delivery at %s.",
+			"message_not_paused":         "This message is not paused.",
+			"series_ended":               "This recurring message has no occurrences left.",
+			"vacation_help":              "Usage: /vacation <from> to <until> or /vacation off\nHolds all your messages while you are away, e.g. /vacation 2025-07-01 09:00 to 2025-07-14 18:00. Recurring messages continue with their next occurrence afterwards.",
+			"vacation_set":               "🏖 Vacation mode from %s until %s.",
+			"vacation_off":               "✅ Vacation mode turned off, held messages were resumed.",
+			"webhook_help":               "Usage:\n/webhook add <url> - Receive events at a URL\n/webhook list - Show your webhooks\n/webhook remove <id> - Remove a webhook\n/webhook log - Show recent deliveries\n\nEvents are POSTed as JSON when a message is created, delivered, failed or cancelled. The X-Riko-Signature header holds sha256=<HMAC-SHA256 of the body with your secret>.",
+			"webhook_added":              "✅ Webhook added for %s\n🆔 %s\n🔑 Secret: %s\nKeep the secret safe, it is shown only once.",
+			"webhook_invalid":            "Invalid webhook URL (it must be a public https address), or you already have the maximum number of webhooks.",
+			"webhook_removed":            "Webhook removed.",
+			"webhook_not_found":          "Webhook not found.",
+			"your_webhooks":              "🔗 Your webhooks:",
+			"no_webhooks":                "You have no webhooks.",
+			"webhook_log":                "📜 Recent webhook deliveries (attempts):",
+			"no_webhook_deliveries":      "No webhook deliveries yet.",
+			"invalid_cron":               "❌ Invalid cron expression: %v\nUse 5 fields (minute hour day month weekday) with an optional leading second (a single value), e.g. \"30 8 * * 1-5\" for weekdays at 08:30.",
+			"invalid_rrule":              "❌ Invalid RRULE: %v\nExample: RRULE:FREQ=MONTHLY;BYDAY=2TU for the second Tuesday of every month.",
+			"rrule_no_occurrences":       "❌ This rule has no occurrences after the start time.",
+			"invalid_interval":           "❌ Invalid interval. Use 1 to 1000 units, e.g. every 3 hours or every 2 weeks on Monday; weekdays only work with weeks.",
+			"preview_help":               "Usage: /preview <message_id> [count]\nLists the next deliveries of a message and its reminders.",
+			"preview_schedule":           "👁 Preview",
+			"upcoming_occurrences":       "📆 Upcoming deliveries (%s):",
+			"no_upcoming_occurrences":    "This message has no upcoming deliveries.",
 		},
 		models.LanguageArabic: {
-			"welcome":               "🌟 أهلاً بك في بوت الرسائل المستقبلية! 🌟\n\nأساعدك في جدولة الرسائل لإرسالها في المستقبل.",
-			"help_text":             "استخدم /new لإنشاء رسالة، /list لعرض الرسائل المعلقة، و /settings لتكوين تفضيلاتك.",
-			"new_message":           "📝 رسالة جديدة",
-			"my_messages":           "📋 رسائلي",
-			"settings":              "⚙️ الإعدادات",
-			"help":                  "❓ المساعدة",
-			"unknown_command":       "أمر غير معروف. اكتب /help لرؤية الأوامر المتاحة.",
-			"new_message_help":      "الاستخدام: /new <الرسالة> at <الوقت>\nمثال: /new مرحبا بالعالم at 2024-01-01 15:30",
-			"invalid_format":        "تنسيق غير صحيح. استخدم: <الرسالة> at <الوقت>",
-			"invalid_time_format":   "تنسيق وقت غير صحيح. أمثلة: 'غداً 9:00'، 'بعد ساعتين'، '2024-01-01 15:30'",
-			"error_occurred":        "حدث خطأ. يرجى المحاولة مرة أخرى.",
-			"message_scheduled":     "✅ تم جدولة الرسالة لـ %s\n🆔 المعرف: %s",
-			"add_notification":      "🔔 إضافة تنبيه",
-			"make_recurring":        "🔄 جعلها متكررة",
-			"send_to_other":         "👤 إرسال لشخص آخر",
-			"no_pending_messages":   "ليس لديك رسائل معلقة.",
-			"your_pending_messages": "📋 رسائلك المعلقة:",
-			"message":               "رسالة",
-			"cancel_help":           "الاستخدام: /cancel <معرف_الرسالة>",
-			"delete_help":           "الاستخدام: /delete <معرف_الرسالة>",
-			"message_not_found":     "الرسالة غير موجودة.",
-			"message_cancelled":     "تم إلغاء الرسالة بنجاح.",
-			"message_deleted":       "تم حذف الرسالة بنجاح.",
-			"change_language":       "تغيير اللغة",
-			"change_timezone":       "تغيير المنطقة الزمنية",
-			"integrations":          "التكاملات",
-			"current_settings":      "🛠 الإعدادات الحالية:\n🌍 اللغة: %s\n🕒 المنطقة الزمنية: %s",
-			"detailed_help":         "🤖 مساعدة بوت الرسائل المستقبلية\n\n📝 الأوامر:\n/new <رسالة> at <وقت> - جدولة رسالة\n/list - عرض الرسائل المعلقة\n/cancel <معرف> - إلغاء رسالة\n/delete <معرف> - حذف رسالة\n/settings - تكوين الإعدادات\n\n⏰ تنسيقات الوقت:\n- 'بعد ساعتين'\n- 'غداً 9:00'\n- '2024-01-01 15:30'\n- 'الجمعة القادمة 14:00'",
-			"new_message_prompt":    "يرجى إرسال رسالتك بالتنسيق:\n<الرسالة> at <الوقت>",
-			"unclear_message":       "لم أفهم. استخدم /help لمعرفة كيفية استخدامي.",
+			"welcome":                    "🌟 أهلاً بك في بوت الرسائل المستقبلية! 🌟\n\nأساعدك في جدولة الرسائل لإرسالها في المستقبل.",
+			"help_text":                  "استخدم /new لإنشاء رسالة، /list لعرض الرسائل المعلقة، و /settings لتكوين تفضيلاتك.",
+			"new_message":                "📝 رسالة جديدة",
+			"my_messages":                "📋 رسائلي",
+			"settings":                   "⚙️ الإعدادات",
+			"help":                       "❓ المساعدة",
+			"unknown_command":            "أمر غير معروف. اكتب /help لرؤية الأوامر المتاحة.",
+			"new_message_help":           "الاستخدام: /new <الرسالة> at <الوقت> [every \"<cron>\"]\nمثال: /new مرحبا بالعالم at 2024-01-01 15:30\nتكرار: /new الاجتماع اليومي every \"30 8 * * 1-5\"\nأيام العمل: /new الاجتماع اليومي في 9:00 كل يوم عمل\nفترات: /new اشرب الماء كل ساعتين، /new مراجعة في 10:00 كل أسبوعين يوم الاثنين\nوقت عشوائي: /new تمارين التمدد بين 09:00 و 11:00 كل يوم",
+			"invalid_format":             "تنسيق غير صحيح. استخدم: <الرسالة> at <الوقت>",
+			"invalid_time_format":        "تنسيق وقت غير صحيح. أمثلة: 'غداً 9:00'، 'بعد ساعتين'، '2024-01-01 15:30'",
+			"time_in_past":               "❌ هذا الوقت قد مضى بالفعل. يرجى اختيار وقت في المستقبل.",
+			"window_needs_day":           "❌ فترة الإرسال تحدد وقت اليوم، لذا اذكر اليوم فقط معها، مثل /new تمارين في غداً بين 09:00 و11:00",
+			"error_occurred":             "حدث خطأ. يرجى المحاولة مرة أخرى.",
+			"message_scheduled":          "✅ تم جدولة الرسالة لـ %s\n🆔 المعرف: %s",
+			"add_notification":           "🔔 إضافة تنبيه",
+			"make_recurring":             "🔄 جعلها متكررة",
+			"send_to_other":              "👤 إرسال لشخص آخر",
+			"no_pending_messages":        "ليس لديك رسائل معلقة.",
+			"your_pending_messages":      "📋 رسائلك المعلقة:",
+			"message":                    "رسالة",
+			"cancel_help":                "الاستخدام: /cancel <معرف_الرسالة> [this|following|all]",
+			"delete_help":                "الاستخدام: /delete <معرف_الرسالة>",
+			"message_not_found":          "الرسالة غير موجودة.",
+			"message_cancelled":          "تم إلغاء الرسالة بنجاح.",
+			"edit_help":                  "الاستخدام: /edit <معرف_الرسالة> [this|following|all] <النص الجديد>\nللسلاسل المتكررة: this = هذا الموعد فقط، following = هذا وكل ما بعده، all = السلسلة كاملة.",
+			"message_edited":             "✅ تم تحديث الرسالة.",
+			"series_cancel_prompt":       "🔁 هذه الرسالة جزء من سلسلة متكررة. ماذا تريد أن تلغي؟",
+			"scope_this":                 "هذا الموعد فقط",
+			"scope_following":            "هذا وما يليه",
+			"scope_all":                  "السلسلة كاملة",
+			"series_cancelled_this":      "✅ تم إلغاء هذا الموعد؛ السلسلة مستمرة.",
+			"series_cancelled_following": "✅ تم إلغاء هذا الموعد وكل ما يليه.",
+			"series_cancelled_all":       "✅ تم إلغاء السلسلة كاملة.",
+			"message_deleted":            "تم حذف الرسالة بنجاح.",
+			"change_language":            "تغيير اللغة",
+			"change_timezone":            "تغيير المنطقة الزمنية",
+			"integrations":               "التكاملات",
+			"current_settings":           "🛠 الإعدادات الحالية:\n🌍 اللغة: %s\n🕒 المنطقة الزمنية: %s",
+			"detailed_help":              "🤖 مساعدة بوت الرسائل المستقبلية\n\n📝 الأوامر:\n/new <رسالة> at <وقت> - جدولة رسالة\n/new <رسالة> [at <وقت>] every \"<cron>\" - تكرار حسب جدول cron\n/new <رسالة> at <وقت> RRULE:<قاعدة> [EXDATE:<أوقات>] - تكرار حسب قاعدة iCalendar\n/new <رسالة> at <وقت> كل يوم عمل - تكرار في أيام العمل\n/new <رسالة> [في <وقت>] كل <مدة> [يوم <أيام>] - تكرار كل عدة دقائق أو ساعات أو أيام أو أسابيع أو أشهر أو سنوات\n/list - عرض الرسائل المعلقة\n/edit <معرف> [this|following|all] <نص> - تعديل رسالة أو سلسلة\n/cancel <معرف> [this|following|all] - إلغاء رسالة أو سلسلة\n/delete <معرف> - حذف رسالة\n/misfire <سياسة> [معرف] - التعامل مع الرسائل المتأخرة\n/workday <skip|previous|next|off> <معرف> - إبعاد الرسالة عن العطل\n/weekend <أيام> - تعيين أيام عطلتك الأسبوعية\n/holidays [تقويم|off] - اختيار تقويم العطل الرسمية\n/pause <معرف> - إيقاف رسالة مؤقتاً\n/resume <معرف> - استئناف رسالة موقوفة\n/vacation <من> إلى <حتى>|off - إيقاف كل الرسائل أثناء الغياب\n/preview <معرف> [عدد] - عرض مواعيد الإرسال القادمة\n/remind <معرف> <مدد|off> - تذكيرك قبل إرسال الرسالة\n/snooze <معرف> <وقت> - إعادة إرسال رسالة مستلمة لاحقاً\n/selfdestruct <معرف> <مدة|off> - حذف الرسالة بعد إرسالها\n/window <معرف> <مدة|off> - الإرسال في وقت عشوائي ضمن فترة\n/webhook <add|list|remove|log> - إدارة الـ webhooks\n/settings - تكوين الإعدادات\n\n⏰ تنسيقات الوقت:\n- 'بعد ساعتين'\n- 'بعد ساعة ونصف' أو 'بعد يوم و3 ساعات'\n- 'بعد شهر' (نفس اليوم من الشهر القادم)\n- 'غداً 9:00' أو 'غداً الساعة ٩ مساءً'\n- '2024-01-01 15:30'\n- 'الجمعة القادمة 14:00'",
+			"new_message_prompt":         "يرجى إرسال رسالتك بالتنسيق:\n<الرسالة> at <الوقت>",
+			"unclear_message":            "لم أفهم. استخدم /help لمعرفة كيفية استخدامي.",
+			"message_retry_scheduled":    "🔁 تمت إعادة جدولة الرسالة لمحاولة إرسال جديدة.",
+			"message_not_failed":         "هذه الرسالة لم تفشل، فلا يوجد ما يُعاد إرساله.",
+			"misfire_help":               "الاستخدام: /misfire <fire_now|skip|coalesce> [معرف_الرسالة]\nيحدد ما يحدث للرسائل المتأخرة بعد توقف الخدمة:\n- fire_now: إرسال كل التكرارات الفائتة\n- skip: تجاهل التكرارات الفائتة وانتظار التالي\n- coalesce: الإرسال مرة واحدة ثم المتابعة مع التكرار التالي\nبدون معرف رسالة يتم تعيين الإعداد الافتراضي لك.",
+			"misfire_updated":            "✅ تم تعيين سياسة التأخير للرسالة إلى %s.",
+			"misfire_default_updated":    "✅ سياسة التأخير الافتراضية لديك الآن %s.",
+			"workday_help":               "الاستخدام: /workday <skip|previous|next|off> <معرف_الرسالة>\nيحدد ما يحدث عندما تقع الرسالة في عطلة:\n- skip: تجاهل هذا التكرار (للرسائل المتكررة فقط)\n- previous: الإرسال في يوم العمل السابق\n- next: الإرسال في يوم العمل التالي\n- off: الإرسال في أي يوم",
+			"workday_set":                "✅ تم تعيين سياسة أيام العمل إلى %s. الإرسال القادم: %s",
+			"workday_off":                "✅ لم تعد الرسالة مقيدة بأيام العمل.",
+			"workday_skip_one_off":       "❌ التجاهل متاح للرسائل المتكررة فقط؛ استخدم previous أو next بدلاً منه.",
+			"weekend_help":               "الاستخدام: /weekend <أيام>\nمثال: /weekend fri,sat أو /weekend none",
+			"weekend_set":                "✅ عطلتك الأسبوعية الآن: %s",
+			"holidays_help":              "الاستخدام: /holidays <تقويم|off>\nالتقاويم المتاحة: %s",
+			"holidays_set":               "✅ تم تعيين تقويم العطل إلى %s.",
+			"holidays_off":               "✅ تم إيقاف تقويم العطل.",
+			"holidays_unknown":           "❌ تقويم عطل غير معروف: %s",
+			"selfdestruct_help":          "الاستخدام: /selfdestruct <معرف_الرسالة> <مدة|off>\nيحذف الرسالة من المحادثة بعد المدة المحددة من إرسالها، مثل /selfdestruct 1a2b3c4d 30 دقيقة أو /selfdestruct 1a2b3c4d 2h.",
+			"selfdestruct_set":           "💣 سيتم حذف الرسالة تلقائياً بعد %s من إرسالها.",
+			"selfdestruct_off":           "✅ تم إيقاف الحذف التلقائي للرسالة.",
+			"window_help":                "الاستخدام: /window <معرف_الرسالة> <مدة|off>\nيرسل الرسالة في وقت عشوائي خلال المدة المحددة (حتى 24 ساعة) بعد موعدها.\nمثال: /window 1a2b3c4d 2h",
+			"window_set":                 "✅ سترسل الرسالة في وقت عشوائي خلال %s من موعدها.",
+			"window_off":                 "✅ سترسل الرسالة في موعدها المحدد.",
+			"remind_help":                "الاستخدام: /remind <معرف_الرسالة> <مدد|off>\nيذكرك قبل إرسال الرسالة، مثل /remind 1a2b3c4d 1 يوم, 1 ساعة, 10 دقيقة.",
+			"reminders_set":              "🔔 سيتم تذكيرك قبل إرسال الرسالة بـ %s.",
+			"reminders_off":              "✅ تم إيقاف التذكيرات للرسالة.",
+			"message_not_pending":        "هذه الرسالة لم تعد معلقة.",
+			"message_sent_now":           "📤 تم إرسال الرسالة.",
+			"message_postponed":          "⏳ تم تأجيل الرسالة إلى %s.",
+			"snooze_help":                "الاستخدام: /snooze <معرف_الرسالة> <وقت>\nيعيد إرسال رسالة مستلمة إليك، مثل /snooze 1a2b3c4d بعد 2 ساعة.",
+			"snooze_custom_prompt":       "أرسل /snooze %s <وقت>، مثل /snooze %s غداً 18:00",
+			"message_snoozed":            "💤 تم التأجيل حتى %s.",
+			"pause_help":                 "الاستخدام: /pause <معرف_الرسالة>",
+			"resume_help":                "الاستخدام: /resume <معرف_الرسالة>",
+			"message_paused":             "⏸ تم إيقاف الرسالة مؤقتاً. استخدم /resume لاستئنافها.",
+			"message_resumed":            "▶️ تم استئناف الرسالة، الإرسال التالي في %s.",
+			"message_not_paused":         "هذه الرسالة ليست موقوفة.",
+			"series_ended":               "لم يتبق أي تكرار لهذه الرسالة المتكررة.",
+			"vacation_help":              "الاستخدام: /vacation <من> إلى <حتى> أو /vacation off\nيوقف كل رسائلك أثناء غيابك، مثل /vacation 2025-07-01 09:00 إلى 2025-07-14 18:00. تستمر الرسائل المتكررة من تكرارها التالي بعد ذلك.",
+			"vacation_set":               "🏖 وضع الإجازة من %s حتى %s.",
+			"vacation_off":               "✅ تم إيقاف وضع الإجازة واستئناف الرسائل الموقوفة.",
+			"webhook_help":               "الاستخدام:\n/webhook add <رابط> - استقبال الأحداث على رابط\n/webhook list - عرض الـ webhooks\n/webhook remove <معرف> - حذف webhook\n/webhook log - عرض آخر عمليات الإرسال\n\nتُرسل الأحداث بصيغة JSON عند إنشاء الرسالة أو إرسالها أو فشلها أو إلغائها. يحتوي الترويسة X-Riko-Signature على sha256=<HMAC-SHA256 للمحتوى باستخدام المفتاح السري>.",
+			"webhook_added":              "✅ تمت إضافة webhook لـ %s\n🆔 %s\n🔑 المفتاح السري: %s\nاحتفظ بالمفتاح بأمان، فهو يظهر مرة واحدة فقط.",
+			"webhook_invalid":            "رابط webhook غير صالح (يجب أن يكون عنوان https عاماً)، أو لديك الحد الأقصى من الـ webhooks.",
+			"webhook_removed":            "تم حذف الـ webhook.",
+			"webhook_not_found":          "الـ webhook غير موجود.",
+			"your_webhooks":              "🔗 الـ webhooks الخاصة بك:",
+			"no_webhooks":                "ليس لديك أي webhooks.",
+			"webhook_log":                "📜 آخر عمليات إرسال الـ webhooks (المحاولات):",
+			"no_webhook_deliveries":      "لا توجد عمليات إرسال بعد.",
+			"invalid_cron":               "❌ تعبير cron غير صالح: %v\nاستخدم 5 حقول (دقيقة ساعة يوم شهر يوم_الأسبوع) مع ثانية اختيارية في البداية (قيمة واحدة)، مثل \"30 8 * * 1-5\" لأيام العمل الساعة 08:30.",
+			"invalid_rrule":              "❌ قاعدة RRULE غير صالحة: %v\nمثال: RRULE:FREQ=MONTHLY;BYDAY=2TU لثاني ثلاثاء من كل شهر.",
+			"rrule_no_occurrences":       "❌ هذه القاعدة لا تتكرر بعد وقت البدء.",
+			"invalid_interval":           "❌ فترة تكرار غير صالحة. استخدم من 1 إلى 1000، مثل كل 3 ساعات أو كل أسبوعين يوم الاثنين؛ أيام الأسبوع تعمل مع الأسابيع فقط.",
+			"preview_help":               "الاستخدام: /preview <معرف_الرسالة> [عدد]\nيعرض مواعيد الإرسال القادمة للرسالة وتذكيراتها.",
+			"preview_schedule":           "👁 معاينة",
+			"upcoming_occurrences":       "📆 مواعيد الإرسال القادمة (%s):",
+			"no_upcoming_occurrences":    "لا توجد مواعيد إرسال قادمة لهذه الرسالة.",
 		},
 	}
 
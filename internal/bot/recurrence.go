@@ -0,0 +1,234 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+// cronPattern matches a trailing `every "<cron expression>"`. Telegram
+// clients may turn straight quotes into typographic ones.
+var cronPattern = regexp.MustCompile(`(?i)\s*every\s+["“”']([^"“”']+)["“”']\s*$`)
+
+// splitCron removes a trailing cron clause from the arguments of /new and
+// returns the remaining arguments and the expression, if any.
+func splitCron(args string) (string, string) {
+	match := cronPattern.FindStringSubmatchIndex(args)
+	if match == nil {
+		return args, ""
+	}
+	return strings.TrimSpace(args[:match[0]]), strings.TrimSpace(args[match[2]:match[3]])
+}
+
+// rrulePattern matches trailing iCalendar lines such as
+// `RRULE:FREQ=MONTHLY;BYDAY=-1FR EXDATE:20240126T090000Z`.
+var rrulePattern = regexp.MustCompile(`(?i)(?:^|\s+)((?:(?:DTSTART|RRULE|EXDATE)[;:]\S+\s*)+)$`)
+
+// splitRRule removes trailing RRULE, EXDATE and DTSTART lines from the
+// arguments of /new and returns the remaining arguments and the lines.
+func splitRRule(args string) (string, string) {
+	match := rrulePattern.FindStringSubmatchIndex(args)
+	if match == nil {
+		return args, ""
+	}
+	return strings.TrimSpace(args[:match[0]]), strings.TrimSpace(args[match[2]:match[3]])
+}
+
+// workdaysPattern matches a trailing "every workday" clause.
+var workdaysPattern = regexp.MustCompile(`(?i)\s+(?:every\s+(?:workday|working\s+day|weekday)s?|كل\s+(?:يوم|أيام)\s+(?:عمل|العمل))\s*$`)
+
+// splitWorkdays removes a trailing "every workday" clause from the arguments
+// of /new and reports whether there was one.
+func splitWorkdays(args string) (string, bool) {
+	match := workdaysPattern.FindStringIndex(args)
+	if match == nil {
+		return args, false
+	}
+	return strings.TrimSpace(args[:match[0]]), true
+}
+
+// maxInterval bounds the number of units between occurrences
+const maxInterval = 1000
+
+// interval is a parsed "every 3 hours" clause. Weekdays restrict a weekly
+// interval to those days.
+type interval struct {
+	unit     models.RecurrenceType
+	every    int
+	weekdays []time.Weekday
+}
+
+const (
+	weekdayNames       = `(?:sun|mon|tues?|wed|thu(?:rs?)?|fri|sat)(?:day|nesday|sday|urday)?s?`
+	arabicWeekdayNames = `(?:الأحد|الاحد|الاثنين|الإثنين|الثلاثاء|الأربعاء|الاربعاء|الخميس|الجمعة|السبت)`
+)
+
+var (
+	intervalPattern = regexp.MustCompile(`(?i)\s+every\s+(?:(other|\d+)\s+)?(minute|min|hour|hr|day|week|month|year)s?` +
+		`(?:\s+on\s+(` + weekdayNames + `(?:\s*(?:,|\band\b)\s*` + weekdayNames + `)*))?\s*$`)
+	arabicIntervalPattern = regexp.MustCompile(`\s+كل\s+(?:(\d+)\s+)?(` + arabicUnitNames() + `)` +
+		`(?:\s+(?:يوم\s+)?(` + arabicWeekdayNames + `(?:(?:\s*[,،]\s*|\s+و\s*|\s*و)` + arabicWeekdayNames + `)*))?\s*$`)
+	weekdayPattern = regexp.MustCompile(`(?i)` + weekdayNames + `|` + arabicWeekdayNames)
+)
+
+var intervalUnits = map[string]models.RecurrenceType{
+	"minute": models.RecurrenceMinutely,
+	"min":    models.RecurrenceMinutely,
+	"hour":   models.RecurrenceHourly,
+	"hr":     models.RecurrenceHourly,
+	"day":    models.RecurrenceDaily,
+	"week":   models.RecurrenceWeekly,
+	"month":  models.RecurrenceMonthly,
+	"year":   models.RecurrenceYearly,
+}
+
+// arabicUnit is an Arabic unit word. Singular and dual forms carry their
+// count; plural forms take it from the number before them.
+type arabicUnit struct {
+	unit  models.RecurrenceType
+	count int
+}
+
+var arabicIntervalUnits = map[string]arabicUnit{
+	"دقيقة":   {models.RecurrenceMinutely, 1},
+	"دقيقتين": {models.RecurrenceMinutely, 2},
+	"دقيقتان": {models.RecurrenceMinutely, 2},
+	"دقائق":   {models.RecurrenceMinutely, 1},
+	"ساعة":    {models.RecurrenceHourly, 1},
+	"ساعتين":  {models.RecurrenceHourly, 2},
+	"ساعتان":  {models.RecurrenceHourly, 2},
+	"ساعات":   {models.RecurrenceHourly, 1},
+	"يوم":     {models.RecurrenceDaily, 1},
+	"يومين":   {models.RecurrenceDaily, 2},
+	"يومان":   {models.RecurrenceDaily, 2},
+	"أيام":    {models.RecurrenceDaily, 1},
+	"ايام":    {models.RecurrenceDaily, 1},
+	"أسبوع":   {models.RecurrenceWeekly, 1},
+	"اسبوع":   {models.RecurrenceWeekly, 1},
+	"أسبوعين": {models.RecurrenceWeekly, 2},
+	"اسبوعين": {models.RecurrenceWeekly, 2},
+	"أسبوعان": {models.RecurrenceWeekly, 2},
+	"أسابيع":  {models.RecurrenceWeekly, 1},
+	"اسابيع":  {models.RecurrenceWeekly, 1},
+	"شهر":     {models.RecurrenceMonthly, 1},
+	"شهرين":   {models.RecurrenceMonthly, 2},
+	"شهران":   {models.RecurrenceMonthly, 2},
+	"أشهر":    {models.RecurrenceMonthly, 1},
+	"اشهر":    {models.RecurrenceMonthly, 1},
+	"شهور":    {models.RecurrenceMonthly, 1},
+	"سنة":     {models.RecurrenceYearly, 1},
+	"سنتين":   {models.RecurrenceYearly, 2},
+	"سنتان":   {models.RecurrenceYearly, 2},
+	"سنوات":   {models.RecurrenceYearly, 1},
+	"سنين":    {models.RecurrenceYearly, 1},
+	"عام":     {models.RecurrenceYearly, 1},
+	"عامين":   {models.RecurrenceYearly, 2},
+	"أعوام":   {models.RecurrenceYearly, 1},
+}
+
+var arabicWeekdays = map[string]time.Weekday{
+	"الأحد":    time.Sunday,
+	"الاحد":    time.Sunday,
+	"الاثنين":  time.Monday,
+	"الإثنين":  time.Monday,
+	"الثلاثاء": time.Tuesday,
+	"الأربعاء": time.Wednesday,
+	"الاربعاء": time.Wednesday,
+	"الخميس":   time.Thursday,
+	"الجمعة":   time.Friday,
+	"السبت":    time.Saturday,
+}
+
+var englishWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// arabicUnitNames lists the Arabic unit words for the interval pattern,
+// longest first so that a dual form is not read as its singular.
+func arabicUnitNames() string {
+	names := make([]string, 0, len(arabicIntervalUnits))
+	for name := range arabicIntervalUnits {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	return strings.Join(names, "|")
+}
+
+// splitInterval removes a trailing "every 3 hours", "every other day",
+// "every 2 weeks on Monday" or "كل ساعتين" clause from the arguments of /new
+// and returns the remaining arguments and the interval, if any.
+func splitInterval(args string) (string, *interval) {
+	if match := intervalPattern.FindStringSubmatch(args); match != nil {
+		iv := &interval{unit: intervalUnits[strings.ToLower(match[2])], every: 1}
+		switch n := strings.ToLower(match[1]); n {
+		case "":
+		case "other":
+			iv.every = 2
+		default:
+			iv.every, _ = strconv.Atoi(n)
+		}
+		iv.weekdays = parseWeekdays(match[3])
+		return strings.TrimSpace(strings.TrimSuffix(args, match[0])), iv
+	}
+
+	if match := arabicIntervalPattern.FindStringSubmatch(args); match != nil {
+		unit := arabicIntervalUnits[match[2]]
+		iv := &interval{unit: unit.unit, every: unit.count}
+		if match[1] != "" {
+			iv.every, _ = strconv.Atoi(match[1])
+		}
+		iv.weekdays = parseWeekdays(match[3])
+		// "كل يوم الاثنين" is every Monday
+		if iv.unit == models.RecurrenceDaily && len(iv.weekdays) > 0 && match[1] == "" {
+			iv.unit = models.RecurrenceWeekly
+		}
+		return strings.TrimSpace(strings.TrimSuffix(args, match[0])), iv
+	}
+
+	return args, nil
+}
+
+func parseWeekdays(list string) []time.Weekday {
+	var weekdays []time.Weekday
+	for _, name := range weekdayPattern.FindAllString(list, -1) {
+		if day, ok := arabicWeekdays[name]; ok {
+			weekdays = append(weekdays, day)
+		} else if day, ok := englishWeekdays[strings.ToLower(name)[:3]]; ok {
+			weekdays = append(weekdays, day)
+		}
+	}
+	return weekdays
+}
+
+// valid reports whether the interval can be scheduled; weekdays only make
+// sense for weekly intervals.
+func (iv *interval) valid() bool {
+	if iv.every < 1 || iv.every > maxInterval {
+		return false
+	}
+	return len(iv.weekdays) == 0 || iv.unit == models.RecurrenceWeekly
+}
+
+// rrule returns the weekly RRULE for an interval on several weekdays.
+func (iv *interval) rrule() string {
+	codes := [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+	days := make([]string, len(iv.weekdays))
+	for i, day := range iv.weekdays {
+		days[i] = codes[day]
+	}
+	return fmt.Sprintf("RRULE:FREQ=WEEKLY;INTERVAL=%d;BYDAY=%s", iv.every, strings.Join(days, ","))
+}
+
+// onWeekday moves t forward to the first day that is weekday, keeping its
+// wall-clock time.
+func onWeekday(t time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(t.Weekday()) + 7) % 7
+	return utils.LocalTime(t.Year(), t.Month(), t.Day()+days, t.Hour(), t.Minute(), t.Second(), t.Location())
+}
@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+func TestSplitInterval(t *testing.T) {
+	cases := []struct {
+		args     string
+		rest     string
+		want     *interval
+		wantNone bool
+	}{
+		{args: "Drink water every 2 hours", rest: "Drink water",
+			want: &interval{unit: models.RecurrenceHourly, every: 2}},
+		{args: "Stand up every 45 min", rest: "Stand up",
+			want: &interval{unit: models.RecurrenceMinutely, every: 45}},
+		{args: "Stretch every other day", rest: "Stretch",
+			want: &interval{unit: models.RecurrenceDaily, every: 2}},
+		{args: "Rent at 1 March 9am every month", rest: "Rent at 1 March 9am",
+			want: &interval{unit: models.RecurrenceMonthly, every: 1}},
+		{args: "Review at 10:00 every 2 weeks on Monday", rest: "Review at 10:00",
+			want: &interval{unit: models.RecurrenceWeekly, every: 2, weekdays: []time.Weekday{time.Monday}}},
+		{args: "Review every 2 weeks on Monday", rest: "Review",
+			want: &interval{unit: models.RecurrenceWeekly, every: 2, weekdays: []time.Weekday{time.Monday}}},
+		{args: "Gym EVERY WEEK ON mon, wed and Fridays", rest: "Gym",
+			want: &interval{unit: models.RecurrenceWeekly, every: 1, weekdays: []time.Weekday{time.Monday, time.Wednesday, time.Friday}}},
+		{args: "Call mum every day on tuesday", rest: "Call mum",
+			want: &interval{unit: models.RecurrenceDaily, every: 1, weekdays: []time.Weekday{time.Tuesday}}},
+		{args: "اشرب الماء كل ساعتين", rest: "اشرب الماء",
+			want: &interval{unit: models.RecurrenceHourly, every: 2}},
+		{args: "مراجعة كل 3 أيام", rest: "مراجعة",
+			want: &interval{unit: models.RecurrenceDaily, every: 3}},
+		{args: "اجتماع كل يوم الاثنين", rest: "اجتماع",
+			want: &interval{unit: models.RecurrenceWeekly, every: 1, weekdays: []time.Weekday{time.Monday}}},
+		{args: "رياضة كل أسبوع يوم الأحد والثلاثاء", rest: "رياضة",
+			want: &interval{unit: models.RecurrenceWeekly, every: 1, weekdays: []time.Weekday{time.Sunday, time.Tuesday}}},
+		{args: "Hello world at 9:00", wantNone: true},
+		{args: "every 2 hours", wantNone: true},
+		{args: "Plan every 2 fortnights", wantNone: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.args, func(t *testing.T) {
+			rest, got := splitInterval(tc.args)
+			if tc.wantNone {
+				if got != nil {
+					t.Fatalf("splitInterval(%q) = %+v, want no interval", tc.args, got)
+				}
+				if rest != tc.args {
+					t.Fatalf("splitInterval(%q) rest = %q, want the input unchanged", tc.args, rest)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("splitInterval(%q) found no interval", tc.args)
+			}
+			if rest != tc.rest {
+				t.Fatalf("splitInterval(%q) rest = %q, want %q", tc.args, rest, tc.rest)
+			}
+			if got.unit != tc.want.unit || got.every != tc.want.every || !slices.Equal(got.weekdays, tc.want.weekdays) {
+				t.Fatalf("splitInterval(%q) = %+v, want %+v", tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIntervalValid(t *testing.T) {
+	cases := []struct {
+		name string
+		iv   interval
+		want bool
+	}{
+		{name: "hourly", iv: interval{unit: models.RecurrenceHourly, every: 2}, want: true},
+		{name: "weekly on days", iv: interval{unit: models.RecurrenceWeekly, every: 2, weekdays: []time.Weekday{time.Monday}}, want: true},
+		{name: "upper bound", iv: interval{unit: models.RecurrenceDaily, every: maxInterval}, want: true},
+		{name: "zero", iv: interval{unit: models.RecurrenceDaily, every: 0}},
+		{name: "too many", iv: interval{unit: models.RecurrenceDaily, every: maxInterval + 1}},
+		{name: "daily on days", iv: interval{unit: models.RecurrenceDaily, every: 1, weekdays: []time.Weekday{time.Tuesday}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.iv.valid(); got != tc.want {
+				t.Fatalf("valid() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIntervalRRule(t *testing.T) {
+	iv := interval{unit: models.RecurrenceWeekly, every: 2, weekdays: []time.Weekday{time.Monday, time.Thursday}}
+	if got, want := iv.rrule(), "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"; got != want {
+		t.Fatalf("rrule() = %q, want %q", got, want)
+	}
+}
+
+func TestOnWeekday(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name    string
+		from    time.Time
+		weekday time.Weekday
+		want    time.Time
+	}{
+		{name: "same day", from: time.Date(2024, time.January, 1, 9, 0, 0, 0, ny), weekday: time.Monday,
+			want: time.Date(2024, time.January, 1, 9, 0, 0, 0, ny)},
+		{name: "later in the week", from: time.Date(2024, time.January, 1, 9, 0, 0, 0, ny), weekday: time.Friday,
+			want: time.Date(2024, time.January, 5, 9, 0, 0, 0, ny)},
+		{name: "wraps to next week", from: time.Date(2024, time.January, 5, 9, 0, 0, 0, ny), weekday: time.Monday,
+			want: time.Date(2024, time.January, 8, 9, 0, 0, 0, ny)},
+		{name: "keeps wall clock across spring forward", from: time.Date(2024, time.March, 8, 9, 0, 0, 0, ny), weekday: time.Monday,
+			want: time.Date(2024, time.March, 11, 9, 0, 0, 0, ny)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := onWeekday(tc.from, tc.weekday); !got.Equal(tc.want) {
+				t.Fatalf("onWeekday(%v, %v) = %v, want %v", tc.from, tc.weekday, got, tc.want)
+			}
+		})
+	}
+}
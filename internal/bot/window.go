@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// windowPattern matches a trailing "between 09:00 and 11:00" clause.
+var windowPattern = regexp.MustCompile(`(?i)\s+(?:between|from|بين|من)\s+(\d{1,2}:\d{2})\s*(?:and|to|-|–|و|إلى|الى)\s*(\d{1,2}:\d{2})\s*$`)
+
+// deliveryWindow is a parsed "between 09:00 and 11:00" clause, with both
+// times on the wall clock.
+type deliveryWindow struct {
+	from, to string
+}
+
+// splitWindow removes a trailing delivery window clause from the arguments
+// of /new and returns the remaining arguments and the window, if any.
+func splitWindow(args string) (string, *deliveryWindow) {
+	match := windowPattern.FindStringSubmatch(args)
+	if match == nil {
+		return args, nil
+	}
+	return strings.TrimSpace(strings.TrimSuffix(args, match[0])), &deliveryWindow{from: match[1], to: match[2]}
+}
+
+// length returns how long the window stays open. A window that ends before
+// it starts runs past midnight.
+func (w *deliveryWindow) length() (time.Duration, bool) {
+	from, err := time.Parse("15:04", w.from)
+	if err != nil {
+		return 0, false
+	}
+	to, err := time.Parse("15:04", w.to)
+	if err != nil {
+		return 0, false
+	}
+
+	d := to.Sub(from)
+	if d <= 0 {
+		d += 24 * time.Hour
+	}
+	return d, true
+}
+
+// formatWindow formats the time a message is scheduled for, as a range when
+// it has a delivery window.
+func formatWindow(at time.Time, window time.Duration, layout string) string {
+	if window <= 0 {
+		return at.Format(layout)
+	}
+	return at.Format(layout) + "–" + at.Add(window).Format("15:04")
+}
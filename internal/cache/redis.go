@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -11,6 +12,18 @@ import (
 	"github.com/MostafaSensei106/Riko-Chan/config"
 )
 
+// claimScript atomically pops due members by pushing their score forward to
+// the lease deadline. A member that is not acknowledged (removed) before the
+// lease expires becomes due again and can be claimed by another worker.
+// Members are returned together with the score they had before the claim.
+var claimScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'WITHSCORES', 'LIMIT', 0, ARGV[3])
+for i = 1, #due, 2 do
+	redis.call('ZADD', KEYS[1], ARGV[2], due[i])
+end
+return due
+`)
+
 type RedisClient struct {
 	client *redis.Client
 }
@@ -70,6 +83,63 @@ func (r *RedisClient) ZRangeByScore(ctx context.Context, key string, min, max st
 	}).Result()
 }
 
+// ZAddNX adds member only if it is not in the set yet and reports whether it
+// was added.
+func (r *RedisClient) ZAddNX(ctx context.Context, key string, score float64, member interface{}) (bool, error) {
+	data, err := json.Marshal(member)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal member: %w", err)
+	}
+
+	added, err := r.client.ZAddNX(ctx, key, &redis.Z{
+		Score:  score,
+		Member: string(data),
+	}).Result()
+	if err != nil {
+		return false, err
+	}
+	return added > 0, nil
+}
+
+func (r *RedisClient) ZMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.ZRange(ctx, key, 0, -1).Result()
+}
+
+// ZClaim claims up to limit members scored at or below now and leases them
+// until leaseUntil.
+func (r *RedisClient) ZClaim(ctx context.Context, key string, now, leaseUntil float64, limit int64) ([]redis.Z, error) {
+	values, err := claimScript.Run(ctx, r.client, []string{key},
+		strconv.FormatFloat(now, 'f', -1, 64),
+		strconv.FormatFloat(leaseUntil, 'f', -1, 64),
+		limit,
+	).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]redis.Z, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		score, err := strconv.ParseFloat(values[i+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid score for %s: %w", values[i], err)
+		}
+		claimed = append(claimed, redis.Z{Member: values[i], Score: score})
+	}
+	return claimed, nil
+}
+
+// ZHead returns the lowest scored member of key, if any.
+func (r *RedisClient) ZHead(ctx context.Context, key string) (redis.Z, bool, error) {
+	head, err := r.client.ZRangeWithScores(ctx, key, 0, 0).Result()
+	if err != nil {
+		return redis.Z{}, false, err
+	}
+	if len(head) == 0 {
+		return redis.Z{}, false, nil
+	}
+	return head[0], true, nil
+}
+
 func (r *RedisClient) ZRem(ctx context.Context, key string, member interface{}) error {
 	data, err := json.Marshal(member)
 	if err != nil {
@@ -79,6 +149,14 @@ func (r *RedisClient) ZRem(ctx context.Context, key string, member interface{})
 	return r.client.ZRem(ctx, key, string(data)).Err()
 }
 
+func (r *RedisClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+func (r *RedisClient) Subscribe(ctx context.Context, channel string) *redis.PubSub {
+	return r.client.Subscribe(ctx, channel)
+}
+
 func (r *RedisClient) GetClient() *redis.Client {
 	return r.client
 }
@@ -0,0 +1,159 @@
+package calendar
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dateLayout is how holiday dates are keyed and written in YAML files.
+const dateLayout = "2006-01-02"
+
+// Calendar is a named set of holidays, e.g. for a country or a team.
+type Calendar struct {
+	Name string
+	// Weekend overrides the default weekend when the file declares one
+	Weekend *Weekend
+
+	holidays map[string]string
+	// recurring holds yearly holidays, keyed by month and day ("01-07")
+	recurring map[string]string
+}
+
+func newCalendar(name string) *Calendar {
+	return &Calendar{
+		Name:      name,
+		holidays:  make(map[string]string),
+		recurring: make(map[string]string),
+	}
+}
+
+func (c *Calendar) addRange(from, until time.Time, name string, yearly bool) {
+	for day := from; !day.After(until); day = day.AddDate(0, 0, 1) {
+		if yearly {
+			c.recurring[day.Format("01-02")] = name
+		} else {
+			c.holidays[day.Format(dateLayout)] = name
+		}
+	}
+}
+
+// Holiday returns the name of the holiday on the given date, if any.
+func (c *Calendar) Holiday(year int, month time.Month, day int) (string, bool) {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if name, ok := c.holidays[date.Format(dateLayout)]; ok {
+		return name, true
+	}
+	name, ok := c.recurring[date.Format("01-02")]
+	return name, ok
+}
+
+// Registry holds the holiday calendars loaded from disk, by name.
+type Registry struct {
+	calendars map[string]*Calendar
+	// DefaultWeekend applies to users who have not chosen their own
+	DefaultWeekend Weekend
+}
+
+func NewRegistry(defaultWeekend Weekend) *Registry {
+	return &Registry{
+		calendars:      make(map[string]*Calendar),
+		DefaultWeekend: defaultWeekend,
+	}
+}
+
+// LoadDir loads every .ics, .yaml and .yml file in dir. Each file is one
+// calendar named after the file, so holidays/eg.yaml becomes "eg". A missing
+// directory is not an error.
+func (r *Registry) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read holidays directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		name := strings.ToLower(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+		path := filepath.Join(dir, entry.Name())
+
+		var cal *Calendar
+		switch ext {
+		case ".ics":
+			cal, err = loadICS(path, name)
+		case ".yaml", ".yml":
+			cal, err = loadYAML(path, name)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load calendar %s: %w", entry.Name(), err)
+		}
+		r.calendars[name] = cal
+	}
+
+	return nil
+}
+
+// Get returns the calendar with the given name.
+func (r *Registry) Get(name string) (*Calendar, bool) {
+	cal, ok := r.calendars[strings.ToLower(name)]
+	return cal, ok
+}
+
+// Names lists the loaded calendars in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.calendars))
+	for name := range r.calendars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WorkSchedule decides which days are working days for one user.
+type WorkSchedule struct {
+	Weekend  Weekend
+	Holidays *Calendar
+}
+
+// Schedule returns the work schedule for a weekend choice and a calendar
+// name, either of which may be empty. An explicit weekend wins over the
+// calendar's, which wins over the default.
+func (r *Registry) Schedule(weekend, calendarName string) WorkSchedule {
+	schedule := WorkSchedule{Weekend: r.DefaultWeekend}
+	if cal, ok := r.Get(calendarName); ok && calendarName != "" {
+		schedule.Holidays = cal
+		if cal.Weekend != nil {
+			schedule.Weekend = *cal.Weekend
+		}
+	}
+	if parsed, err := ParseWeekend(weekend); err == nil && weekend != "" {
+		schedule.Weekend = parsed
+	}
+	return schedule
+}
+
+// IsWorkingDay reports whether the given date is neither a weekend day nor
+// a holiday.
+func (w WorkSchedule) IsWorkingDay(year int, month time.Month, day int) bool {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if w.Weekend[date.Weekday()] {
+		return false
+	}
+	if w.Holidays != nil {
+		if _, ok := w.Holidays.Holiday(date.Year(), date.Month(), date.Day()); ok {
+			return false
+		}
+	}
+	return true
+}
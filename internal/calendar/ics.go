@@ -0,0 +1,97 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// loadICS reads the VEVENTs of an iCalendar file as holidays. All-day and
+// multi-day events are supported, as are events repeating with
+// RRULE:FREQ=YEARLY.
+func loadICS(path, name string) (*Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Long lines are folded onto continuation lines starting with a space
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	cal := newCalendar(name)
+	var event map[string]string
+	for _, line := range lines {
+		switch {
+		case line == "BEGIN:VEVENT":
+			event = make(map[string]string)
+		case line == "END:VEVENT":
+			if err := addICSEvent(cal, event); err != nil {
+				return nil, err
+			}
+			event = nil
+		case event != nil:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			// Parameters such as ;VALUE=DATE are not needed
+			key, _, _ = strings.Cut(key, ";")
+			event[strings.ToUpper(key)] = value
+		}
+	}
+
+	return cal, nil
+}
+
+func addICSEvent(cal *Calendar, event map[string]string) error {
+	start, err := parseICSDate(event["DTSTART"])
+	if err != nil {
+		return fmt.Errorf("invalid DTSTART %q", event["DTSTART"])
+	}
+
+	// DTEND is exclusive; without it the event lasts one day
+	until := start
+	if value, ok := event["DTEND"]; ok {
+		end, err := parseICSDate(value)
+		if err != nil {
+			return fmt.Errorf("invalid DTEND %q", value)
+		}
+		if end.After(start) {
+			until = end.AddDate(0, 0, -1)
+		}
+	}
+
+	yearly := false
+	if rule, ok := event["RRULE"]; ok {
+		if !strings.Contains(strings.ToUpper(rule), "FREQ=YEARLY") {
+			return fmt.Errorf("unsupported holiday rule %q", rule)
+		}
+		yearly = true
+	}
+
+	cal.addRange(start, until, event["SUMMARY"], yearly)
+	return nil
+}
+
+// parseICSDate takes the date of a DATE or DATE-TIME value.
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return time.Parse("20060102", value[:8])
+}
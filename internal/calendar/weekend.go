@@ -0,0 +1,56 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Weekend marks the days of the week that are not working days.
+type Weekend [7]bool
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseWeekend parses a comma-separated list of days such as "fri,sat".
+// Full names and "none" for a seven-day week are accepted too.
+func ParseWeekend(value string) (Weekend, error) {
+	var weekend Weekend
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "none" {
+		return weekend, nil
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) < 3 {
+			return Weekend{}, fmt.Errorf("invalid weekday %q", name)
+		}
+		day, ok := weekdayNames[name[:3]]
+		if !ok {
+			return Weekend{}, fmt.Errorf("invalid weekday %q", name)
+		}
+		weekend[day] = true
+	}
+
+	if weekend == (Weekend{true, true, true, true, true, true, true}) {
+		return Weekend{}, fmt.Errorf("the weekend cannot be the whole week")
+	}
+	return weekend, nil
+}
+
+// String formats the weekend as ParseWeekend accepts it.
+func (w Weekend) String() string {
+	var days []string
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if w[day] {
+			days = append(days, strings.ToLower(day.String()[:3]))
+		}
+	}
+	if len(days) == 0 {
+		return "none"
+	}
+	return strings.Join(days, ",")
+}
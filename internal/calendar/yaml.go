@@ -0,0 +1,85 @@
+package calendar
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlCalendar is the layout of a YAML holiday file:
+//
+//	weekend: fri,sat
+//	holidays:
+//	  - date: "2025-03-30"
+//	    until: "2025-04-01"
+//	    name: Eid al-Fitr
+//	  - date: "01-07"
+//	    name: Coptic Christmas
+//
+// A date without a year repeats every year.
+type yamlCalendar struct {
+	Weekend  string `yaml:"weekend"`
+	Holidays []struct {
+		Date  string `yaml:"date"`
+		Until string `yaml:"until"`
+		Name  string `yaml:"name"`
+	} `yaml:"holidays"`
+}
+
+func loadYAML(path, name string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file yamlCalendar
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	cal := newCalendar(name)
+	if file.Weekend != "" {
+		weekend, err := ParseWeekend(file.Weekend)
+		if err != nil {
+			return nil, err
+		}
+		cal.Weekend = &weekend
+	}
+
+	for _, holiday := range file.Holidays {
+		start, yearly, err := parseYAMLDate(holiday.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		until := start
+		if holiday.Until != "" {
+			end, endYearly, err := parseYAMLDate(holiday.Until)
+			if err != nil {
+				return nil, err
+			}
+			if endYearly != yearly || end.Before(start) {
+				return nil, fmt.Errorf("invalid range %s to %s", holiday.Date, holiday.Until)
+			}
+			until = end
+		}
+
+		cal.addRange(start, until, holiday.Name, yearly)
+	}
+
+	return cal, nil
+}
+
+// parseYAMLDate parses "2006-01-02", or "01-02" for a yearly holiday.
+func parseYAMLDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, false, nil
+	}
+	// A leap year, so that 02-29 is valid
+	if t, err := time.Parse("2006-01-02", "2000-"+value); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid holiday date %q", value)
+}
@@ -1,35 +1,59 @@
 package db
 
 import (
-	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
 	"github.com/MostafaSensei106/Riko-Chan/config"
 )
 
-func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
+func NewConnection(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
 
-	db, err := sql.Open("postgres", dsn)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	if err := db.Ping(); err != nil {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database handle: %w", err)
+	}
+
+	if err := sqlDB.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	return db, nil
 }
 
-func RunMigrations(db *sql.DB) error {
+func RunMigrations(db *gorm.DB) error {
 	migrationFiles := []string{
 		"001_create_users_table.sql",
 		"002_create_messages_table.sql",
 		"003_add_indexes.sql",
+		"004_add_message_failure_reason.sql",
+		"005_add_message_attempts.sql",
+		"006_add_misfire_policy.sql",
+		"007_create_scheduled_jobs_table.sql",
+		"008_create_deliveries_table.sql",
+		"009_add_message_reminders.sql",
+		"010_add_pause_and_vacation.sql",
+		"011_create_outbox_table.sql",
+		"012_create_webhooks_tables.sql",
+		"013_add_cron_expression.sql",
+		"014_add_recurrence_rule.sql",
+		"015_create_series_table.sql",
+		"016_add_series_start_time.sql",
+		"017_add_working_days.sql",
+		"018_add_recurrence_interval.sql",
+		"019_add_delivery_window.sql",
+		"020_add_message_claim.sql",
 	}
 
 	for _, file := range migrationFiles {
@@ -39,7 +63,7 @@ func RunMigrations(db *sql.DB) error {
 			return fmt.Errorf("failed to read migration file %s: %w", file, err)
 		}
 
-		if _, err := db.Exec(string(content)); err != nil {
+		if err := db.Exec(string(content)).Error; err != nil {
 			return fmt.Errorf("failed to execute migration %s: %w", file, err)
 		}
 	}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+type DeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewDeliveryRepository(db *gorm.DB) *DeliveryRepository {
+	return &DeliveryRepository{db: db}
+}
+
+func (r *DeliveryRepository) Create(delivery *models.Delivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *DeliveryRepository) GetByMessageID(messageID uuid.UUID) (*models.Delivery, error) {
+	var delivery models.Delivery
+	if err := r.db.First(&delivery, "message_id = ?", messageID).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetPendingDeletions returns deliveries that are due to be deleted from
+// Telegram but have not been removed yet.
+func (r *DeliveryRepository) GetPendingDeletions() ([]*models.Delivery, error) {
+	var deliveries []*models.Delivery
+	if err := r.db.
+		Where("delete_at IS NOT NULL AND removed_at IS NULL").
+		Order("delete_at ASC").
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *DeliveryRepository) MarkRemoved(id uuid.UUID, removedAt time.Time) error {
+	return r.db.Model(&models.Delivery{}).
+		Where("id = ?", id).
+		Update("removed_at", removedAt).Error
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -52,6 +53,89 @@ func (r *MessageRepository) GetPendingMessages(before time.Time) ([]*models.Mess
 	return messages, nil
 }
 
+func (r *MessageRepository) GetAllPendingMessages() ([]*models.Message, error) {
+	var messages []*models.Message
+	if err := r.db.
+		Where("status = ?", models.MessageStatusPending).
+		Order("scheduled_time ASC").
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *MessageRepository) GetAllUserPendingMessages(userID int64) ([]*models.Message, error) {
+	var messages []*models.Message
+	if err := r.db.
+		Where("user_id = ? AND status = ?", userID, models.MessageStatusPending).
+		Order("scheduled_time ASC").
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *MessageRepository) GetHeldForVacation(userID int64) ([]*models.Message, error) {
+	var messages []*models.Message
+	if err := r.db.
+		Where("user_id = ? AND status = ? AND held_for_vacation", userID, models.MessageStatusPaused).
+		Order("scheduled_time ASC").
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// FindByShortID returns the ID of the user's message whose ID starts with
+// prefix or, failing that, of the next pending or paused occurrence of the
+// user's series whose ID starts with it.
+func (r *MessageRepository) FindByShortID(userID int64, prefix string) (uuid.UUID, error) {
+	var message models.Message
+	err := r.db.Select("id").
+		Where("user_id = ? AND id::text LIKE ? || '%'", userID, prefix).
+		Order("scheduled_time ASC").
+		First(&message).Error
+	if err == nil {
+		return message.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, err
+	}
+
+	err = r.db.Select("id").
+		Where("user_id = ? AND series_id::text LIKE ? || '%' AND status IN ?", userID, prefix,
+			[]models.MessageStatus{models.MessageStatusPending, models.MessageStatusPaused}).
+		Order("scheduled_time ASC").
+		First(&message).Error
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return message.ID, nil
+}
+
+// GetOpenBySeries returns the pending and paused occurrences of a series.
+func (r *MessageRepository) GetOpenBySeries(seriesID uuid.UUID) ([]*models.Message, error) {
+	var messages []*models.Message
+	if err := r.db.
+		Where("series_id = ? AND status IN ?", seriesID, []models.MessageStatus{models.MessageStatusPending, models.MessageStatusPaused}).
+		Order("scheduled_time ASC").
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *MessageRepository) GetByIDs(ids []uuid.UUID) ([]*models.Message, error) {
+	var messages []*models.Message
+	if len(ids) == 0 {
+		return messages, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
 func (r *MessageRepository) Update(message *models.Message) error {
 	return r.db.Save(message).Error
 }
@@ -60,8 +144,115 @@ func (r *MessageRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.Message{}, "id = ?", id).Error
 }
 
+// Claim leases a pending message to one sender until leaseUntil and reports
+// whether it got the lease; a lease held by another sender until after now
+// is left alone.
+func (r *MessageRepository) Claim(id uuid.UUID, now, leaseUntil time.Time) (bool, error) {
+	result := r.db.Model(&models.Message{}).
+		Where("id = ? AND status = ? AND (claimed_until IS NULL OR claimed_until <= ?)", id, models.MessageStatusPending, now).
+		Update("claimed_until", leaseUntil)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *MessageRepository) ReleaseClaim(id uuid.UUID) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("claimed_until", nil).Error
+}
+
 func (r *MessageRepository) UpdateStatus(id uuid.UUID, status models.MessageStatus) error {
 	return r.db.Model(&models.Message{}).
 		Where("id = ?", id).
 		Update("status", status).Error
 }
+
+func (r *MessageRepository) UpdateMisfirePolicy(id uuid.UUID, policy *models.MisfirePolicy) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("misfire_policy", policy).Error
+}
+
+func (r *MessageRepository) UpdateWorkdayPolicy(id uuid.UUID, policy *models.WorkdayPolicy) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("workday_policy", policy).Error
+}
+
+func (r *MessageRepository) UpdateDeleteAfter(id uuid.UUID, deleteAfter *time.Duration) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("delete_after", deleteAfter).Error
+}
+
+func (r *MessageRepository) UpdateDeliveryWindow(id uuid.UUID, window *time.Duration) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("delivery_window", window).Error
+}
+
+func (r *MessageRepository) UpdateReminders(id uuid.UUID, reminders models.Reminders) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("reminders", reminders).Error
+}
+
+func (r *MessageRepository) UpdateScheduledTime(id uuid.UUID, scheduledTime time.Time) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("scheduled_time", scheduledTime).Error
+}
+
+func (r *MessageRepository) UpdateSeriesID(id uuid.UUID, seriesID uuid.UUID) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Update("series_id", seriesID).Error
+}
+
+func (r *MessageRepository) Pause(id uuid.UUID, heldForVacation bool) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":            models.MessageStatusPaused,
+			"held_for_vacation": heldForVacation,
+		}).Error
+}
+
+func (r *MessageRepository) Resume(id uuid.UUID, scheduledTime time.Time, recurrenceCount int) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":            models.MessageStatusPending,
+			"held_for_vacation": false,
+			"scheduled_time":    scheduledTime,
+			"recurrence_count":  recurrenceCount,
+		}).Error
+}
+
+func (r *MessageRepository) MarkFailed(id uuid.UUID, reason string) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":         models.MessageStatusFailed,
+			"failure_reason": reason,
+		}).Error
+}
+
+func (r *MessageRepository) RecordFailedAttempt(id uuid.UUID, attempts int, reason string) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":       attempts,
+			"failure_reason": reason,
+		}).Error
+}
+
+func (r *MessageRepository) ResetForRetry(id uuid.UUID, scheduledTime time.Time) error {
+	return r.db.Model(&models.Message{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":         models.MessageStatusPending,
+			"scheduled_time": scheduledTime,
+			"attempts":       0,
+			"failure_reason": nil,
+		}).Error
+}
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_messages_user_id_status ON messages (user_id, status);
+CREATE INDEX IF NOT EXISTS idx_messages_status_scheduled_time ON messages (status, scheduled_time);
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS misfire_policy VARCHAR(20) NOT NULL DEFAULT 'fire_now';
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS misfire_policy VARCHAR(20);
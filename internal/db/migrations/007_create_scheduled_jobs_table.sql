@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    queue VARCHAR(64) NOT NULL,
+    member TEXT NOT NULL,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (queue, member)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_queue_run_at ON scheduled_jobs (queue, run_at);
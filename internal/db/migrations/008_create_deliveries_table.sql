@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chat_id BIGINT NOT NULL,
+    telegram_message_id INTEGER NOT NULL,
+    delivered_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delete_at TIMESTAMP WITH TIME ZONE,
+    removed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_deliveries_message_id ON deliveries (message_id);
+CREATE INDEX IF NOT EXISTS idx_deliveries_pending_deletion ON deliveries (delete_at) WHERE removed_at IS NULL;
+
+-- Self-destruct delay in nanoseconds
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS delete_after BIGINT;
@@ -0,0 +1,18 @@
+-- Reminder offsets before the scheduled time, in nanoseconds
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS reminders JSONB NOT NULL DEFAULT '[]';
+
+-- Move notify_before into reminders; migrations run on every startup, so
+-- only while the old column is still there
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'messages' AND column_name = 'notify_before'
+    ) THEN
+        UPDATE messages
+        SET reminders = jsonb_build_array((EXTRACT(EPOCH FROM notify_before) * 1000000000)::BIGINT)
+        WHERE notify_before IS NOT NULL;
+
+        ALTER TABLE messages DROP COLUMN notify_before;
+    END IF;
+END $$;
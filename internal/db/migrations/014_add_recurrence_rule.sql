@@ -0,0 +1,2 @@
+-- DTSTART, RRULE and EXDATE lines in iCalendar (RFC 5545) syntax
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS recurrence_rule TEXT;
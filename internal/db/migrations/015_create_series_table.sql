@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS series (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    recipient_id BIGINT,
+    group_id VARCHAR(255),
+    channel_id VARCHAR(255),
+    message_type VARCHAR(50) NOT NULL,
+    content TEXT NOT NULL,
+    media_file_id VARCHAR(255),
+    location JSONB,
+    recurrence_type VARCHAR(20) NOT NULL,
+    max_recurrences INTEGER,
+    cron_expression VARCHAR(255),
+    recurrence_rule TEXT,
+    misfire_policy VARCHAR(20),
+    reminders JSONB NOT NULL DEFAULT '[]',
+    delete_after BIGINT,
+    private_view_mode BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_user_id ON series (user_id);
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES series(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_messages_series_id ON messages (series_id) WHERE series_id IS NOT NULL;
@@ -0,0 +1,8 @@
+ALTER TABLE series ADD COLUMN IF NOT EXISTS start_time TIMESTAMP WITH TIME ZONE;
+
+UPDATE series SET start_time = COALESCE(
+    (SELECT MIN(scheduled_time) FROM messages WHERE messages.series_id = series.id),
+    created_at
+) WHERE start_time IS NULL;
+
+ALTER TABLE series ALTER COLUMN start_time SET NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS weekend VARCHAR(50);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS holiday_calendar VARCHAR(100);
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS workday_policy VARCHAR(20);
+ALTER TABLE series ADD COLUMN IF NOT EXISTS workday_policy VARCHAR(20);
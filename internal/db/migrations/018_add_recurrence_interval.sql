@@ -0,0 +1,3 @@
+-- Units of recurrence_type between occurrences, e.g. 3 hourly = every 3 hours
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS recurrence_interval INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE series ADD COLUMN IF NOT EXISTS recurrence_interval INTEGER NOT NULL DEFAULT 1;
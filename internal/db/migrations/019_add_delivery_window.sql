@@ -0,0 +1,4 @@
+-- Length of the window after scheduled_time in which delivery is drawn, in
+-- nanoseconds
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS delivery_window BIGINT;
+ALTER TABLE series ADD COLUMN IF NOT EXISTS delivery_window BIGINT;
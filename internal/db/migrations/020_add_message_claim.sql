@@ -0,0 +1,2 @@
+-- Set while one worker delivers the message, so no other sends it too
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMP WITH TIME ZONE;
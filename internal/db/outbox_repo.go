@@ -0,0 +1,61 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) Create(entry *models.OutboxEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// Claim leases undelivered entries due for another attempt that have not run
+// out of attempts, by moving their next attempt to leaseUntil. Entries
+// claimed by another instance are skipped.
+func (r *OutboxRepository) Claim(now, leaseUntil time.Time, maxAttempts, limit int) ([]*models.OutboxEntry, error) {
+	var entries []*models.OutboxEntry
+	if err := r.db.Raw(`
+		WITH due AS (
+			SELECT id FROM outbox
+			WHERE delivered_at IS NULL AND next_attempt_at <= ? AND attempts < ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox o SET next_attempt_at = ?
+		FROM due
+		WHERE o.id = due.id
+		RETURNING o.*`,
+		now, maxAttempts, limit, leaseUntil).Scan(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *OutboxRepository) MarkDelivered(id uuid.UUID, deliveredAt time.Time) error {
+	return r.db.Model(&models.OutboxEntry{}).
+		Where("id = ?", id).
+		Update("delivered_at", deliveredAt).Error
+}
+
+func (r *OutboxRepository) RecordFailure(id uuid.UUID, attempts int, nextAttemptAt time.Time, reason string) error {
+	return r.db.Model(&models.OutboxEntry{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      reason,
+		}).Error
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+type SeriesRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) *SeriesRepository {
+	return &SeriesRepository{db: db}
+}
+
+func (r *SeriesRepository) Create(series *models.Series) error {
+	return r.db.Create(series).Error
+}
+
+func (r *SeriesRepository) GetByID(id uuid.UUID) (*models.Series, error) {
+	var series models.Series
+	if err := r.db.First(&series, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *SeriesRepository) Update(series *models.Series) error {
+	return r.db.Save(series).Error
+}
+
+// UpdateColumn sets one template column, e.g. when a setting changes on an
+// occurrence.
+func (r *SeriesRepository) UpdateColumn(id uuid.UUID, column string, value interface{}) error {
+	return r.db.Model(&models.Series{}).
+		Where("id = ?", id).
+		Update(column, value).Error
+}
+
+func (r *SeriesRepository) UpdateStatus(id uuid.UUID, status models.SeriesStatus) error {
+	return r.db.Model(&models.Series{}).
+		Where("id = ?", id).
+		Update("status", status).Error
+}
@@ -31,6 +31,14 @@ func (r *UserRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+func (r *UserRepository) GetOnVacation() ([]*models.User, error) {
+	var users []*models.User
+	if err := r.db.Where("vacation_until IS NOT NULL").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // func (r *UserRepository) Upsert(user *models.User) error {
 // 	return r.db.Clauses(
 // 		gorm.Clauses{gorm.OnConflict{
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) Create(webhook *models.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *WebhookRepository) GetByUser(userID int64) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) GetByID(id uuid.UUID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := r.db.First(&webhook, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Webhook{}, "id = ?", id).Error
+}
+
+// CreateDelivery queues a webhook call. An event handled again, e.g. when
+// the outbox redelivers it, keeps the delivery already queued for it.
+func (r *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(delivery).Error
+}
+
+// GetDueDeliveries returns undelivered webhook calls due for another attempt
+// that have not run out of attempts.
+func (r *WebhookRepository) GetDueDeliveries(now time.Time, maxAttempts, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	if err := r.db.
+		Where("delivered_at IS NULL AND next_attempt_at <= ? AND attempts < ?", now, maxAttempts).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) GetRecentDeliveries(userID int64, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	if err := r.db.
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(id uuid.UUID, attempts, statusCode int, deliveredAt time.Time) error {
+	return r.db.Model(&models.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":     attempts,
+			"status_code":  statusCode,
+			"delivered_at": deliveredAt,
+			"last_error":   nil,
+		}).Error
+}
+
+func (r *WebhookRepository) RecordDeliveryFailure(id uuid.UUID, attempts int, statusCode *int, nextAttemptAt time.Time, reason string) error {
+	return r.db.Model(&models.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"status_code":     statusCode,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      reason,
+		}).Error
+}
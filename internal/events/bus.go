@@ -0,0 +1,208 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/db"
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+const (
+	// relayInterval is how often undelivered outbox entries are retried
+	relayInterval  = 15 * time.Second
+	relayBatchSize = 100
+	// outboxLease is how long a dispatch owns an entry before the relay of
+	// this or another instance may take it over
+	outboxLease = 2 * time.Minute
+
+	outboxMaxAttempts = 10
+	outboxBaseDelay   = 10 * time.Second
+	outboxMaxDelay    = time.Hour
+)
+
+// Handler reacts to an event. Handlers run on the publisher's goroutine and
+// should hand slow work off instead of blocking it.
+type Handler func(ctx context.Context, event Event) error
+
+type subscriber struct {
+	name    string
+	types   map[Type]bool
+	handler Handler
+}
+
+func (s *subscriber) wants(eventType Type) bool {
+	return len(s.types) == 0 || s.types[eventType]
+}
+
+// Bus delivers domain events to in-process subscribers. Without an outbox a
+// failed handler is only logged; with one, every event is stored per
+// subscriber first and retried until handled, across restarts too.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []*subscriber
+	outbox      *db.OutboxRepository
+	logger      *utils.Logger
+}
+
+func NewBus(logger *utils.Logger) *Bus {
+	return &Bus{logger: logger}
+}
+
+func (b *Bus) SetOutbox(outbox *db.OutboxRepository) {
+	b.outbox = outbox
+}
+
+// Subscribe registers handler for the given event types, or for all of them
+// when none are given. The name identifies the subscriber in the outbox and
+// must stay stable across restarts.
+func (b *Bus) Subscribe(name string, handler Handler, types ...Type) {
+	sub := &subscriber{
+		name:    name,
+		types:   make(map[Type]bool, len(types)),
+		handler: handler,
+	}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, sub)
+}
+
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	subscribers := make([]*subscriber, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		if sub.wants(event.Type) {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range subscribers {
+		if b.outbox == nil {
+			if err := sub.handler(ctx, event); err != nil {
+				b.logger.Error("Event handler failed", "error", err, "subscriber", sub.name, "event", event.Type, "event_id", event.ID)
+			}
+			continue
+		}
+
+		entry, err := newOutboxEntry(sub.name, event)
+		if err != nil {
+			b.logger.Error("Failed to encode event", "error", err, "event", event.Type, "event_id", event.ID)
+			continue
+		}
+		// Dispatched right below; the relay only steps in if that never ends
+		entry.NextAttemptAt = time.Now().Add(outboxLease)
+		if err := b.outbox.Create(entry); err != nil {
+			b.logger.Error("Failed to store event in outbox", "error", err, "subscriber", sub.name, "event_id", event.ID)
+			continue
+		}
+		b.dispatch(ctx, sub, entry, event)
+	}
+}
+
+// Start relays outbox entries whose delivery failed or was interrupted. It
+// only does work when an outbox is set.
+func (b *Bus) Start(ctx context.Context) {
+	if b.outbox == nil {
+		return
+	}
+
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.relay(ctx); err != nil {
+			b.logger.Error("Failed to relay outbox", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Bus) relay(ctx context.Context) error {
+	now := time.Now()
+	entries, err := b.outbox.Claim(now, now.Add(outboxLease), outboxMaxAttempts, relayBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim due outbox entries: %w", err)
+	}
+
+	for _, entry := range entries {
+		sub := b.subscriber(entry.Subscriber)
+		if sub == nil {
+			// The subscriber may be registered by a newer or older build
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(entry.Payload), &event); err != nil {
+			b.logger.Error("Failed to decode outbox entry", "error", err, "entry_id", entry.ID)
+			continue
+		}
+		b.dispatch(ctx, sub, entry, event)
+	}
+	return nil
+}
+
+func (b *Bus) dispatch(ctx context.Context, sub *subscriber, entry *models.OutboxEntry, event Event) {
+	if err := sub.handler(ctx, event); err != nil {
+		attempts := entry.Attempts + 1
+		next := time.Now().Add(outboxDelay(attempts))
+		if err := b.outbox.RecordFailure(entry.ID, attempts, next, err.Error()); err != nil {
+			b.logger.Error("Failed to record outbox failure", "error", err, "entry_id", entry.ID)
+		}
+		if attempts >= outboxMaxAttempts {
+			b.logger.Error("Giving up on event", "error", err, "subscriber", sub.name, "event", event.Type, "event_id", event.ID)
+			return
+		}
+		b.logger.Warn("Event handler failed, will retry", "error", err, "subscriber", sub.name,
+			"event", event.Type, "event_id", event.ID, "retry_at", next)
+		return
+	}
+
+	if err := b.outbox.MarkDelivered(entry.ID, time.Now()); err != nil {
+		b.logger.Error("Failed to mark outbox entry delivered", "error", err, "entry_id", entry.ID)
+	}
+}
+
+func (b *Bus) subscriber(name string) *subscriber {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.subscribers {
+		if sub.name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+func newOutboxEntry(subscriber string, event Event) (*models.OutboxEntry, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewOutboxEntry(event.ID, subscriber, string(event.Type), string(payload)), nil
+}
+
+// outboxDelay returns the exponential backoff before the given attempt.
+func outboxDelay(attempt int) time.Duration {
+	delay := outboxBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= outboxMaxDelay {
+			return outboxMaxDelay
+		}
+	}
+	return delay
+}
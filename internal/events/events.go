@@ -0,0 +1,89 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+type Type string
+
+const (
+	MessageCreated   Type = "message.created"
+	MessageUpdated   Type = "message.updated"
+	MessageCancelled Type = "message.cancelled"
+	MessageDelivered Type = "message.delivered"
+	MessageFailed    Type = "message.failed"
+	NotificationSent Type = "notification.sent"
+)
+
+// Event is something that happened to a scheduled message. It never carries
+// the message content, so it can be stored and sent on as is.
+type Event struct {
+	ID            uuid.UUID            `json:"id"`
+	Type          Type                 `json:"type"`
+	MessageID     uuid.UUID            `json:"message_id"`
+	UserID        int64                `json:"user_id"`
+	Status        models.MessageStatus `json:"status"`
+	ScheduledTime time.Time            `json:"scheduled_time"`
+	OccurredAt    time.Time            `json:"occurred_at"`
+
+	// Set on MessageDelivered
+	ChatID            int64 `json:"chat_id,omitempty"`
+	TelegramMessageID int   `json:"telegram_message_id,omitempty"`
+	// Set on MessageFailed
+	Attempts int    `json:"attempts,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// Set on NotificationSent
+	Offset time.Duration `json:"offset,omitempty"`
+}
+
+func newEvent(eventType Type, message *models.Message) Event {
+	return Event{
+		ID:            uuid.New(),
+		Type:          eventType,
+		MessageID:     message.ID,
+		UserID:        message.UserID,
+		Status:        message.Status,
+		ScheduledTime: message.ScheduledTime,
+		OccurredAt:    time.Now(),
+	}
+}
+
+func NewMessageCreated(message *models.Message) Event {
+	return newEvent(MessageCreated, message)
+}
+
+func NewMessageUpdated(message *models.Message) Event {
+	return newEvent(MessageUpdated, message)
+}
+
+func NewMessageCancelled(message *models.Message) Event {
+	event := newEvent(MessageCancelled, message)
+	event.Status = models.MessageStatusCancelled
+	return event
+}
+
+func NewMessageDelivered(message *models.Message, chatID int64, telegramMessageID int) Event {
+	event := newEvent(MessageDelivered, message)
+	event.Status = models.MessageStatusSent
+	event.ChatID = chatID
+	event.TelegramMessageID = telegramMessageID
+	return event
+}
+
+func NewMessageFailed(message *models.Message, attempts int, reason string) Event {
+	event := newEvent(MessageFailed, message)
+	event.Status = models.MessageStatusFailed
+	event.Attempts = attempts
+	event.Reason = reason
+	return event
+}
+
+func NewNotificationSent(message *models.Message, offset time.Duration) Event {
+	event := newEvent(NotificationSent, message)
+	event.Offset = offset
+	return event
+}
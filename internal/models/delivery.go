@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Delivery records where a scheduled message ended up in Telegram, so it can
+// be edited, pinned or deleted afterwards.
+type Delivery struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	MessageID         uuid.UUID  `json:"message_id" db:"message_id"`
+	UserID            int64      `json:"user_id" db:"user_id"`
+	ChatID            int64      `json:"chat_id" db:"chat_id"`
+	TelegramMessageID int        `json:"telegram_message_id" db:"telegram_message_id"`
+	DeliveredAt       time.Time  `json:"delivered_at" db:"delivered_at"`
+	DeleteAt          *time.Time `json:"delete_at" db:"delete_at"`
+	RemovedAt         *time.Time `json:"removed_at" db:"removed_at"`
+}
+
+func NewDelivery(message *Message, chatID int64, telegramMessageID int) *Delivery {
+	return &Delivery{
+		ID:                uuid.New(),
+		MessageID:         message.ID,
+		UserID:            message.UserID,
+		ChatID:            chatID,
+		TelegramMessageID: telegramMessageID,
+		DeliveredAt:       time.Now(),
+	}
+}
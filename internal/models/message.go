@@ -1,6 +1,12 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,13 +25,58 @@ const (
 type RecurrenceType string
 
 const (
-	RecurrenceNone    RecurrenceType = "none"
-	RecurrenceDaily   RecurrenceType = "daily"
-	RecurrenceWeekly  RecurrenceType = "weekly"
-	RecurrenceMonthly RecurrenceType = "monthly"
-	RecurrenceYearly  RecurrenceType = "yearly"
+	RecurrenceNone     RecurrenceType = "none"
+	RecurrenceMinutely RecurrenceType = "minutely"
+	RecurrenceHourly   RecurrenceType = "hourly"
+	RecurrenceDaily    RecurrenceType = "daily"
+	RecurrenceWeekly   RecurrenceType = "weekly"
+	RecurrenceMonthly  RecurrenceType = "monthly"
+	RecurrenceYearly   RecurrenceType = "yearly"
+	// RecurrenceCron follows Message.CronExpression
+	RecurrenceCron RecurrenceType = "cron"
+	// RecurrenceRRule follows the iCalendar lines in Message.RecurrenceRule
+	RecurrenceRRule RecurrenceType = "rrule"
+	// RecurrenceWorkdays repeats daily on the owner's working days
+	RecurrenceWorkdays RecurrenceType = "workdays"
 )
 
+type MisfirePolicy string
+
+const (
+	// MisfireFireNow delivers every overdue occurrence as soon as possible
+	MisfireFireNow MisfirePolicy = "fire_now"
+	// MisfireSkip drops overdue occurrences and waits for the next one
+	MisfireSkip MisfirePolicy = "skip"
+	// MisfireCoalesce delivers overdue occurrences once, then moves on
+	MisfireCoalesce MisfirePolicy = "coalesce"
+)
+
+func (p MisfirePolicy) IsValid() bool {
+	switch p {
+	case MisfireFireNow, MisfireSkip, MisfireCoalesce:
+		return true
+	}
+	return false
+}
+
+// WorkdayPolicy decides what happens to an occurrence that falls on a
+// weekend day or holiday of the owner.
+type WorkdayPolicy string
+
+const (
+	WorkdaySkip     WorkdayPolicy = "skip"
+	WorkdayPrevious WorkdayPolicy = "previous"
+	WorkdayNext     WorkdayPolicy = "next"
+)
+
+func (p WorkdayPolicy) IsValid() bool {
+	switch p {
+	case WorkdaySkip, WorkdayPrevious, WorkdayNext:
+		return true
+	}
+	return false
+}
+
 type MessageStatus string
 
 const (
@@ -33,6 +84,8 @@ const (
 	MessageStatusSent      MessageStatus = "sent"
 	MessageStatusCancelled MessageStatus = "cancelled"
 	MessageStatusFailed    MessageStatus = "failed"
+	MessageStatusMissed    MessageStatus = "missed"
+	MessageStatusPaused    MessageStatus = "paused"
 )
 
 type Location struct {
@@ -42,41 +95,124 @@ type Location struct {
 	Address   string  `json:"address,omitempty"`
 }
 
+// Reminders are offsets before the scheduled time at which the owner is
+// reminded of an upcoming message. They are stored as a JSON array.
+type Reminders []time.Duration
+
+func (r Reminders) Contains(offset time.Duration) bool {
+	for _, reminder := range r {
+		if reminder == offset {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Reminders) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]time.Duration(r))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (r *Reminders) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported reminders type %T", value)
+	}
+	return json.Unmarshal(data, (*[]time.Duration)(r))
+}
+
 type Message struct {
-	ID               uuid.UUID      `json:"id" db:"id"`
-	UserID           int64          `json:"user_id" db:"user_id"`
-	RecipientID      *int64         `json:"recipient_id" db:"recipient_id"`
-	GroupID          *string        `json:"group_id" db:"group_id"`
-	ChannelID        *string        `json:"channel_id" db:"channel_id"`
-	MessageType      MessageType    `json:"message_type" db:"message_type"`
-	Content          string         `json:"content" db:"content"`
-	MediaFileID      *string        `json:"media_file_id" db:"media_file_id"`
-	Location         *Location      `json:"location" db:"location"`
-	ScheduledTime    time.Time      `json:"scheduled_time" db:"scheduled_time"`
-	Status           MessageStatus  `json:"status" db:"status"`
-	RecurrenceType   RecurrenceType `json:"recurrence_type" db:"recurrence_type"`
-	RecurrenceCount  int            `json:"recurrence_count" db:"recurrence_count"`
-	MaxRecurrences   *int           `json:"max_recurrences" db:"max_recurrences"`
-	NotifyBefore     *time.Duration `json:"notify_before" db:"notify_before"`
-	PrivateViewMode  bool           `json:"private_view_mode" db:"private_view_mode"`
-	GoogleCalendarID *string        `json:"google_calendar_id" db:"google_calendar_id"`
-	NotionPageID     *string        `json:"notion_page_id" db:"notion_page_id"`
-	TrelloCardID     *string        `json:"trello_card_id" db:"trello_card_id"`
-	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt        time.Time      `json:"updated_at" db:"updated_at"`
+	ID                 uuid.UUID      `json:"id" db:"id"`
+	UserID             int64          `json:"user_id" db:"user_id"`
+	RecipientID        *int64         `json:"recipient_id" db:"recipient_id"`
+	GroupID            *string        `json:"group_id" db:"group_id"`
+	ChannelID          *string        `json:"channel_id" db:"channel_id"`
+	MessageType        MessageType    `json:"message_type" db:"message_type"`
+	Content            string         `json:"content" db:"content"`
+	MediaFileID        *string        `json:"media_file_id" db:"media_file_id"`
+	Location           *Location      `json:"location" db:"location"`
+	ScheduledTime      time.Time      `json:"scheduled_time" db:"scheduled_time"`
+	Status             MessageStatus  `json:"status" db:"status"`
+	SeriesID           *uuid.UUID     `json:"series_id" db:"series_id"`
+	HeldForVacation    bool           `json:"held_for_vacation" db:"held_for_vacation"`
+	FailureReason      *string        `json:"failure_reason" db:"failure_reason"`
+	Attempts           int            `json:"attempts" db:"attempts"`
+	RecurrenceType     RecurrenceType `json:"recurrence_type" db:"recurrence_type"`
+	RecurrenceCount    int            `json:"recurrence_count" db:"recurrence_count"`
+	RecurrenceInterval int            `json:"recurrence_interval" db:"recurrence_interval"`
+	MaxRecurrences     *int           `json:"max_recurrences" db:"max_recurrences"`
+	CronExpression     *string        `json:"cron_expression" db:"cron_expression"`
+	RecurrenceRule     *string        `json:"recurrence_rule" db:"recurrence_rule"`
+	MisfirePolicy      *MisfirePolicy `json:"misfire_policy" db:"misfire_policy"`
+	WorkdayPolicy      *WorkdayPolicy `json:"workday_policy" db:"workday_policy"`
+	Reminders          Reminders      `json:"reminders" db:"reminders"`
+	DeleteAfter        *time.Duration `json:"delete_after" db:"delete_after"`
+	DeliveryWindow     *time.Duration `json:"delivery_window" db:"delivery_window"`
+	PrivateViewMode    bool           `json:"private_view_mode" db:"private_view_mode"`
+	GoogleCalendarID   *string        `json:"google_calendar_id" db:"google_calendar_id"`
+	NotionPageID       *string        `json:"notion_page_id" db:"notion_page_id"`
+	TrelloCardID       *string        `json:"trello_card_id" db:"trello_card_id"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Interval returns how many units of the recurrence type lie between
+// occurrences, e.g. 3 for an hourly message sent every 3 hours.
+func (m *Message) Interval() int {
+	if m.RecurrenceInterval < 1 {
+		return 1
+	}
+	return m.RecurrenceInterval
+}
+
+// DeliveryTime returns when the message goes out. With a delivery window it
+// is a uniformly random time in the window that opens at ScheduledTime. The
+// draw is seeded by the series and the window start, so an occurrence gets
+// the same time however often it is scheduled.
+func (m *Message) DeliveryTime() time.Time {
+	if m.DeliveryWindow == nil || *m.DeliveryWindow < time.Second {
+		return m.ScheduledTime
+	}
+
+	key := m.ID
+	if m.SeriesID != nil {
+		key = *m.SeriesID
+	}
+	seed := fnv.New64a()
+	seed.Write(key[:])
+	binary.Write(seed, binary.BigEndian, m.ScheduledTime.Unix())
+
+	rng := rand.New(rand.NewSource(int64(seed.Sum64())))
+	offset := time.Duration(rng.Int63n(int64(*m.DeliveryWindow/time.Second))) * time.Second
+	return m.ScheduledTime.Add(offset)
 }
 
 func NewMessage(userID int64, messageType MessageType, content string) *Message {
 	return &Message{
-		ID:              uuid.New(),
-		UserID:          userID,
-		MessageType:     messageType,
-		Content:         content,
-		Status:          MessageStatusPending,
-		RecurrenceType:  RecurrenceNone,
-		RecurrenceCount: 0,
-		PrivateViewMode: false,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		ID:                 uuid.New(),
+		UserID:             userID,
+		MessageType:        messageType,
+		Content:            content,
+		Status:             MessageStatusPending,
+		RecurrenceType:     RecurrenceNone,
+		RecurrenceCount:    0,
+		RecurrenceInterval: 1,
+		PrivateViewMode:    false,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
 	}
 }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEntry is a domain event waiting to be handled by one subscriber.
+type OutboxEntry struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	EventID       uuid.UUID  `json:"event_id" db:"event_id"`
+	Subscriber    string     `json:"subscriber" db:"subscriber"`
+	EventType     string     `json:"event_type" db:"event_type"`
+	Payload       string     `json:"payload" db:"payload"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	LastError     *string    `json:"last_error" db:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at" db:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+func (OutboxEntry) TableName() string {
+	return "outbox"
+}
+
+func NewOutboxEntry(eventID uuid.UUID, subscriber, eventType, payload string) *OutboxEntry {
+	return &OutboxEntry{
+		ID:            uuid.New(),
+		EventID:       eventID,
+		Subscriber:    subscriber,
+		EventType:     eventType,
+		Payload:       payload,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+}
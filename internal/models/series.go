@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SeriesStatus string
+
+const (
+	SeriesStatusActive SeriesStatus = "active"
+	// SeriesStatusEnded series create no further occurrences
+	SeriesStatusEnded     SeriesStatus = "ended"
+	SeriesStatusCancelled SeriesStatus = "cancelled"
+)
+
+// Series is a recurring message. It owns the template each occurrence is
+// created from; occurrences are messages that reference it. StartTime is the
+// first occurrence, whose wall-clock time later ones keep.
+type Series struct {
+	ID                 uuid.UUID      `json:"id" db:"id"`
+	UserID             int64          `json:"user_id" db:"user_id"`
+	Status             SeriesStatus   `json:"status" db:"status"`
+	RecipientID        *int64         `json:"recipient_id" db:"recipient_id"`
+	GroupID            *string        `json:"group_id" db:"group_id"`
+	ChannelID          *string        `json:"channel_id" db:"channel_id"`
+	MessageType        MessageType    `json:"message_type" db:"message_type"`
+	Content            string         `json:"content" db:"content"`
+	MediaFileID        *string        `json:"media_file_id" db:"media_file_id"`
+	Location           *Location      `json:"location" db:"location"`
+	RecurrenceType     RecurrenceType `json:"recurrence_type" db:"recurrence_type"`
+	RecurrenceInterval int            `json:"recurrence_interval" db:"recurrence_interval"`
+	MaxRecurrences     *int           `json:"max_recurrences" db:"max_recurrences"`
+	CronExpression     *string        `json:"cron_expression" db:"cron_expression"`
+	RecurrenceRule     *string        `json:"recurrence_rule" db:"recurrence_rule"`
+	MisfirePolicy      *MisfirePolicy `json:"misfire_policy" db:"misfire_policy"`
+	WorkdayPolicy      *WorkdayPolicy `json:"workday_policy" db:"workday_policy"`
+	Reminders          Reminders      `json:"reminders" db:"reminders"`
+	DeleteAfter        *time.Duration `json:"delete_after" db:"delete_after"`
+	DeliveryWindow     *time.Duration `json:"delivery_window" db:"delivery_window"`
+	PrivateViewMode    bool           `json:"private_view_mode" db:"private_view_mode"`
+	StartTime          time.Time      `json:"start_time" db:"start_time"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+func (Series) TableName() string {
+	return "series"
+}
+
+// NewSeries creates a series whose template is taken from message.
+func NewSeries(message *Message) *Series {
+	return &Series{
+		ID:                 uuid.New(),
+		UserID:             message.UserID,
+		Status:             SeriesStatusActive,
+		RecipientID:        message.RecipientID,
+		GroupID:            message.GroupID,
+		ChannelID:          message.ChannelID,
+		MessageType:        message.MessageType,
+		Content:            message.Content,
+		MediaFileID:        message.MediaFileID,
+		Location:           message.Location,
+		RecurrenceType:     message.RecurrenceType,
+		RecurrenceInterval: message.RecurrenceInterval,
+		MaxRecurrences:     message.MaxRecurrences,
+		CronExpression:     message.CronExpression,
+		RecurrenceRule:     message.RecurrenceRule,
+		MisfirePolicy:      message.MisfirePolicy,
+		WorkdayPolicy:      message.WorkdayPolicy,
+		Reminders:          message.Reminders,
+		DeleteAfter:        message.DeleteAfter,
+		DeliveryWindow:     message.DeliveryWindow,
+		PrivateViewMode:    message.PrivateViewMode,
+		StartTime:          message.ScheduledTime,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+}
+
+// Apply returns a copy of occurrence with the template fields of the series,
+// dropping any changes made to that occurrence alone.
+func (s *Series) Apply(occurrence *Message) *Message {
+	message := *occurrence
+	message.SeriesID = &s.ID
+	message.RecipientID = s.RecipientID
+	message.GroupID = s.GroupID
+	message.ChannelID = s.ChannelID
+	message.MessageType = s.MessageType
+	message.Content = s.Content
+	message.MediaFileID = s.MediaFileID
+	message.Location = s.Location
+	message.RecurrenceType = s.RecurrenceType
+	message.RecurrenceInterval = s.RecurrenceInterval
+	message.MaxRecurrences = s.MaxRecurrences
+	message.CronExpression = s.CronExpression
+	message.RecurrenceRule = s.RecurrenceRule
+	message.MisfirePolicy = s.MisfirePolicy
+	message.WorkdayPolicy = s.WorkdayPolicy
+	message.Reminders = s.Reminders
+	message.DeleteAfter = s.DeleteAfter
+	message.DeliveryWindow = s.DeliveryWindow
+	message.PrivateViewMode = s.PrivateViewMode
+	return &message
+}
@@ -11,26 +11,40 @@ const (
 )
 
 type User struct {
-	ID           int64        `json:"id"`
-	Username     *string      `json:"username"`
-	FirstName    string       `json:"first_name"`
-	LastName     *string      `json:"last_name"`
-	Language     UserLanguage `json:"language"`
-	Timezone     string       `json:"timezone"`
-	GoogleTokens *string      `json:"google_tokens"`
-	NotionToken  *string      `json:"notion_token"`
-	TrelloToken  *string      `json:"trello_token"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    time.Time    `json:"updated_at"`
+	ID              int64         `json:"id"`
+	Username        *string       `json:"username"`
+	FirstName       string        `json:"first_name"`
+	LastName        *string       `json:"last_name"`
+	Language        UserLanguage  `json:"language"`
+	Timezone        string        `json:"timezone"`
+	MisfirePolicy   MisfirePolicy `json:"misfire_policy"`
+	VacationFrom    *time.Time    `json:"vacation_from"`
+	VacationUntil   *time.Time    `json:"vacation_until"`
+	Weekend         *string       `json:"weekend"`
+	HolidayCalendar *string       `json:"holiday_calendar"`
+	GoogleTokens    *string       `json:"google_tokens"`
+	NotionToken     *string       `json:"notion_token"`
+	TrelloToken     *string       `json:"trello_token"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
 }
 
 func NewUser(id int64, firstName string) *User {
 	return &User{
-		ID:        id,
-		FirstName: firstName,
-		Language:  LanguageEnglish,
-		Timezone:  "UTC",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:            id,
+		FirstName:     firstName,
+		Language:      LanguageEnglish,
+		Timezone:      "UTC",
+		MisfirePolicy: MisfireFireNow,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
 	}
 }
+
+// OnVacation reports whether the user's messages are held at time t.
+func (u *User) OnVacation(t time.Time) bool {
+	if u.VacationFrom == nil || u.VacationUntil == nil {
+		return false
+	}
+	return !t.Before(*u.VacationFrom) && t.Before(*u.VacationUntil)
+}
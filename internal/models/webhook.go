@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook is an HTTP endpoint of a user that receives signed message
+// lifecycle events.
+type Webhook struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	URL       string    `json:"url" db:"url"`
+	Secret    string    `json:"-" db:"secret"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+func NewWebhook(userID int64, url, secret string) *Webhook {
+	return &Webhook{
+		ID:        uuid.New(),
+		UserID:    userID,
+		URL:       url,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}
+}
+
+// WebhookDelivery is one event sent, or still to be sent, to a webhook.
+type WebhookDelivery struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	WebhookID     uuid.UUID  `json:"webhook_id" db:"webhook_id"`
+	UserID        int64      `json:"user_id" db:"user_id"`
+	EventID       uuid.UUID  `json:"event_id" db:"event_id"`
+	EventType     string     `json:"event_type" db:"event_type"`
+	Payload       string     `json:"payload" db:"payload"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	StatusCode    *int       `json:"status_code" db:"status_code"`
+	LastError     *string    `json:"last_error" db:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at" db:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+func NewWebhookDelivery(webhook *Webhook, eventID uuid.UUID, eventType, payload string) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhook.ID,
+		UserID:        webhook.UserID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+}
@@ -0,0 +1,118 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryBackend keeps queues in process memory. It suits single-node and
+// development setups; scheduled jobs do not survive a restart, but the
+// reconciler rebuilds them from the database at startup.
+type MemoryBackend struct {
+	mu     sync.Mutex
+	queues map[string]map[Job]time.Time
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		queues: make(map[string]map[Job]time.Time),
+	}
+}
+
+func (b *MemoryBackend) queue(name string) map[Job]time.Time {
+	q, ok := b.queues[name]
+	if !ok {
+		q = make(map[Job]time.Time)
+		b.queues[name] = q
+	}
+	return q
+}
+
+func (b *MemoryBackend) Add(ctx context.Context, queue string, job Job, at time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.queue(queue)[job] = at
+	return nil
+}
+
+func (b *MemoryBackend) AddIfMissing(ctx context.Context, queue string, job Job, at time.Time) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queue(queue)
+	if _, exists := q[job]; exists {
+		return false, nil
+	}
+	q[job] = at
+	return true, nil
+}
+
+func (b *MemoryBackend) Remove(ctx context.Context, queue string, job Job) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.queue(queue), job)
+	return nil
+}
+
+func (b *MemoryBackend) Claim(ctx context.Context, queue string, now, leaseUntil time.Time, limit int) ([]ClaimedJob, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queue(queue)
+	var due []ClaimedJob
+	for job, at := range q {
+		if !at.After(now) {
+			due = append(due, ClaimedJob{Job: job, Due: at})
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].Due.Before(due[j].Due) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, claim := range due {
+		q[claim.Job] = leaseUntil
+	}
+	return due, nil
+}
+
+func (b *MemoryBackend) Head(ctx context.Context, queue string) (time.Time, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var head time.Time
+	found := false
+	for _, at := range b.queue(queue) {
+		if !found || at.Before(head) {
+			head, found = at, true
+		}
+	}
+	return head, found, nil
+}
+
+func (b *MemoryBackend) Jobs(ctx context.Context, queue string) ([]Job, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queue(queue)
+	jobs := make([]Job, 0, len(q))
+	for job := range q {
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// Publish is a no-op: there are no other instances to tell, and the local
+// scheduler already wakes itself.
+func (b *MemoryBackend) Publish(ctx context.Context, at time.Time) error {
+	return nil
+}
+
+func (b *MemoryBackend) Subscribe(ctx context.Context) (<-chan time.Time, func()) {
+	return nil, func() {}
+}
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+// PostgresBackend keeps queues in the scheduled_jobs table. Claims use
+// SELECT ... FOR UPDATE SKIP LOCKED so several instances can share the table
+// without Redis.
+type PostgresBackend struct {
+	db     *gorm.DB
+	logger *utils.Logger
+}
+
+func NewPostgresBackend(db *gorm.DB, logger *utils.Logger) *PostgresBackend {
+	return &PostgresBackend{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type scheduledJobRow struct {
+	Member string
+	RunAt  time.Time
+}
+
+func encodeJob(job Job) (string, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal job: %w", err)
+	}
+	return string(data), nil
+}
+
+func (b *PostgresBackend) Add(ctx context.Context, queue string, job Job, at time.Time) error {
+	member, err := encodeJob(job)
+	if err != nil {
+		return err
+	}
+
+	return b.db.WithContext(ctx).Exec(`
+		INSERT INTO scheduled_jobs (queue, member, run_at) VALUES (?, ?, ?)
+		ON CONFLICT (queue, member) DO UPDATE SET run_at = EXCLUDED.run_at`,
+		queue, member, at).Error
+}
+
+func (b *PostgresBackend) AddIfMissing(ctx context.Context, queue string, job Job, at time.Time) (bool, error) {
+	member, err := encodeJob(job)
+	if err != nil {
+		return false, err
+	}
+
+	result := b.db.WithContext(ctx).Exec(`
+		INSERT INTO scheduled_jobs (queue, member, run_at) VALUES (?, ?, ?)
+		ON CONFLICT (queue, member) DO NOTHING`,
+		queue, member, at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (b *PostgresBackend) Remove(ctx context.Context, queue string, job Job) error {
+	member, err := encodeJob(job)
+	if err != nil {
+		return err
+	}
+
+	return b.db.WithContext(ctx).Exec(
+		`DELETE FROM scheduled_jobs WHERE queue = ? AND member = ?`, queue, member).Error
+}
+
+func (b *PostgresBackend) Claim(ctx context.Context, queue string, now, leaseUntil time.Time, limit int) ([]ClaimedJob, error) {
+	var rows []scheduledJobRow
+	if err := b.db.WithContext(ctx).Raw(`
+		WITH due AS (
+			SELECT queue, member, run_at FROM scheduled_jobs
+			WHERE queue = ? AND run_at <= ?
+			ORDER BY run_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE scheduled_jobs j SET run_at = ?
+		FROM due
+		WHERE j.queue = due.queue AND j.member = due.member
+		RETURNING j.member, due.run_at`,
+		queue, now, limit, leaseUntil).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	claimed := make([]ClaimedJob, 0, len(rows))
+	for _, row := range rows {
+		var job Job
+		if err := json.Unmarshal([]byte(row.Member), &job); err != nil {
+			b.dropMalformed(ctx, queue, row.Member, err)
+			continue
+		}
+		claimed = append(claimed, ClaimedJob{Job: job, Due: row.RunAt})
+	}
+	return claimed, nil
+}
+
+func (b *PostgresBackend) Head(ctx context.Context, queue string) (time.Time, bool, error) {
+	var rows []scheduledJobRow
+	if err := b.db.WithContext(ctx).Raw(
+		`SELECT member, run_at FROM scheduled_jobs WHERE queue = ? ORDER BY run_at LIMIT 1`,
+		queue).Scan(&rows).Error; err != nil {
+		return time.Time{}, false, err
+	}
+	if len(rows) == 0 {
+		return time.Time{}, false, nil
+	}
+	return rows[0].RunAt, true, nil
+}
+
+func (b *PostgresBackend) Jobs(ctx context.Context, queue string) ([]Job, error) {
+	var rows []scheduledJobRow
+	if err := b.db.WithContext(ctx).Raw(
+		`SELECT member, run_at FROM scheduled_jobs WHERE queue = ?`, queue).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, 0, len(rows))
+	for _, row := range rows {
+		var job Job
+		if err := json.Unmarshal([]byte(row.Member), &job); err != nil {
+			b.dropMalformed(ctx, queue, row.Member, err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (b *PostgresBackend) dropMalformed(ctx context.Context, queue, member string, err error) {
+	b.logger.Error("Failed to unmarshal scheduled job", "error", err, "queue", queue, "member", member)
+	if err := b.db.WithContext(ctx).Exec(
+		`DELETE FROM scheduled_jobs WHERE queue = ? AND member = ?`, queue, member).Error; err != nil {
+		b.logger.Error("Failed to drop malformed job", "error", err, "queue", queue)
+	}
+}
+
+// Publish is a no-op; other instances pick new jobs up within the
+// scheduler's idle wait.
+func (b *PostgresBackend) Publish(ctx context.Context, at time.Time) error {
+	return nil
+}
+
+func (b *PostgresBackend) Subscribe(ctx context.Context) (<-chan time.Time, func()) {
+	return nil, func() {}
+}
@@ -0,0 +1,231 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+// reconcileInterval is how often the backend's queues are compared against
+// the messages table, in addition to the pass at startup.
+const reconcileInterval = 10 * time.Minute
+
+// runReconciler rebuilds the schedule once at startup and then periodically,
+// so a flushed or restarted backend never loses pending messages for long.
+func (s *Scheduler) runReconciler(ctx context.Context) {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Reconcile(ctx); err != nil {
+			s.logger.Error("Failed to reconcile schedule", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile makes the queues match the database: every pending message (and
+// its reminders) missing from the backend is added back, and jobs whose
+// message was sent, cancelled or deleted are dropped.
+func (s *Scheduler) Reconcile(ctx context.Context) error {
+	pending, err := s.repo.GetAllPendingMessages()
+	if err != nil {
+		return fmt.Errorf("failed to get pending messages: %w", err)
+	}
+
+	restored := 0
+	for _, message := range pending {
+		added, err := s.ensureScheduled(ctx, message)
+		if err != nil {
+			s.logger.Error("Failed to restore scheduled message", "error", err, "message_id", message.ID)
+			continue
+		}
+		if added {
+			restored++
+		}
+	}
+
+	deletions, err := s.deliveryRepo.GetPendingDeletions()
+	if err != nil {
+		return fmt.Errorf("failed to get pending deletions: %w", err)
+	}
+
+	for _, delivery := range deletions {
+		job := Job{
+			MessageID: delivery.MessageID,
+			UserID:    delivery.UserID,
+		}
+		added, err := s.backend.AddIfMissing(ctx, DeletionsQueue, job, *delivery.DeleteAt)
+		if err != nil {
+			s.logger.Error("Failed to restore scheduled deletion", "error", err, "message_id", delivery.MessageID)
+			continue
+		}
+		if added {
+			restored++
+		}
+	}
+
+	if s.userRepo != nil {
+		restored += s.ensureVacations(ctx)
+	}
+
+	removed := 0
+	for _, queue := range []string{MessagesQueue, NotificationsQueue, DeadLetterQueue} {
+		n, err := s.removeStale(ctx, queue)
+		if err != nil {
+			s.logger.Error("Failed to remove stale jobs", "error", err, "queue", queue)
+			continue
+		}
+		removed += n
+	}
+
+	n, err := s.removeStaleDeletions(ctx, deletions)
+	if err != nil {
+		s.logger.Error("Failed to remove stale jobs", "error", err, "queue", DeletionsQueue)
+	}
+	removed += n
+
+	s.logger.Info("Schedule reconciled", "pending", len(pending), "restored", restored, "removed", removed)
+	return nil
+}
+
+// ensureScheduled adds the message and its reminders only where they are
+// missing, so claim leases and retry backoff already queued are preserved.
+func (s *Scheduler) ensureScheduled(ctx context.Context, message *models.Message) (bool, error) {
+	job := Job{
+		MessageID: message.ID,
+		UserID:    message.UserID,
+	}
+
+	added, err := s.backend.AddIfMissing(ctx, MessagesQueue, job, message.DeliveryTime())
+	if err != nil {
+		return false, fmt.Errorf("failed to schedule message: %w", err)
+	}
+	if added {
+		s.notifyScheduled(ctx, message.DeliveryTime())
+	}
+
+	for reminder, at := range reminderJobs(message) {
+		if _, err := s.backend.AddIfMissing(ctx, NotificationsQueue, reminder, at); err != nil {
+			s.logger.Error("Failed to restore reminder", "error", err, "message_id", message.ID)
+		}
+	}
+
+	return added, nil
+}
+
+// ensureVacations restores the end of every vacation on record, and the
+// start of those that have not begun yet.
+func (s *Scheduler) ensureVacations(ctx context.Context) int {
+	users, err := s.userRepo.GetOnVacation()
+	if err != nil {
+		s.logger.Error("Failed to get users on vacation", "error", err)
+		return 0
+	}
+
+	restored := 0
+	for _, user := range users {
+		job := Job{UserID: user.ID}
+		if user.VacationFrom != nil && user.VacationFrom.After(time.Now()) {
+			if added, err := s.backend.AddIfMissing(ctx, VacationStartsQueue, job, *user.VacationFrom); err != nil {
+				s.logger.Error("Failed to restore vacation start", "error", err, "user_id", user.ID)
+			} else if added {
+				restored++
+			}
+		}
+		if added, err := s.backend.AddIfMissing(ctx, VacationEndsQueue, job, *user.VacationUntil); err != nil {
+			s.logger.Error("Failed to restore vacation end", "error", err, "user_id", user.ID)
+		} else if added {
+			restored++
+		}
+	}
+
+	return restored
+}
+
+// removeStale drops jobs on queue whose message no longer exists or is no
+// longer in the status that queue expects.
+func (s *Scheduler) removeStale(ctx context.Context, queue string) (int, error) {
+	queued, err := s.backend.Jobs(ctx, queue)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list %s: %w", queue, err)
+	}
+
+	// A message can have several jobs on a queue, e.g. one per reminder
+	jobs := make(map[uuid.UUID][]Job, len(queued))
+	ids := make([]uuid.UUID, 0, len(queued))
+	for _, job := range queued {
+		if _, ok := jobs[job.MessageID]; !ok {
+			ids = append(ids, job.MessageID)
+		}
+		jobs[job.MessageID] = append(jobs[job.MessageID], job)
+	}
+
+	messages, err := s.repo.GetByIDs(ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	expected := models.MessageStatusPending
+	if queue == DeadLetterQueue {
+		expected = models.MessageStatusFailed
+	}
+
+	for _, message := range messages {
+		if message.Status == expected {
+			delete(jobs, message.ID)
+		}
+	}
+
+	removed := 0
+	for id, stale := range jobs {
+		for _, job := range stale {
+			if err := s.backend.Remove(ctx, queue, job); err != nil {
+				return removed, fmt.Errorf("failed to remove stale job %s: %w", id, err)
+			}
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// removeStaleDeletions drops deletion jobs whose delivery was already removed
+// or no longer exists.
+func (s *Scheduler) removeStaleDeletions(ctx context.Context, pending []*models.Delivery) (int, error) {
+	queued, err := s.backend.Jobs(ctx, DeletionsQueue)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list %s: %w", DeletionsQueue, err)
+	}
+
+	expected := make(map[uuid.UUID]bool, len(pending))
+	for _, delivery := range pending {
+		expected[delivery.MessageID] = true
+	}
+
+	removed := 0
+	for _, job := range queued {
+		if expected[job.MessageID] {
+			continue
+		}
+		// The delivery may have been recorded after the pending list was read
+		if delivery, err := s.deliveryRepo.GetByMessageID(job.MessageID); err == nil && delivery.DeleteAt != nil && delivery.RemovedAt == nil {
+			continue
+		}
+		if err := s.backend.Remove(ctx, DeletionsQueue, job); err != nil {
+			return removed, fmt.Errorf("failed to remove stale job %s: %w", job.MessageID, err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/cache"
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+// WakeupChannel carries the score of newly scheduled jobs so that every
+// scheduler instance can wake up early when something sooner is added.
+const WakeupChannel = "scheduler_wakeup"
+
+// RedisBackend keeps each queue in a sorted set scored by due time.
+type RedisBackend struct {
+	redis  *cache.RedisClient
+	logger *utils.Logger
+}
+
+func NewRedisBackend(redis *cache.RedisClient, logger *utils.Logger) *RedisBackend {
+	return &RedisBackend{
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+// timeScore converts a time into a sorted set score with sub-second precision.
+func timeScore(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+func scoreTime(score float64) time.Time {
+	return time.Unix(0, int64(score*float64(time.Second)))
+}
+
+func (b *RedisBackend) Add(ctx context.Context, queue string, job Job, at time.Time) error {
+	return b.redis.ZAdd(ctx, queue, timeScore(at), job)
+}
+
+func (b *RedisBackend) AddIfMissing(ctx context.Context, queue string, job Job, at time.Time) (bool, error) {
+	return b.redis.ZAddNX(ctx, queue, timeScore(at), job)
+}
+
+func (b *RedisBackend) Remove(ctx context.Context, queue string, job Job) error {
+	return b.redis.ZRem(ctx, queue, job)
+}
+
+func (b *RedisBackend) Claim(ctx context.Context, queue string, now, leaseUntil time.Time, limit int) ([]ClaimedJob, error) {
+	members, err := b.redis.ZClaim(ctx, queue, timeScore(now), timeScore(leaseUntil), int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]ClaimedJob, 0, len(members))
+	for _, member := range members {
+		raw, _ := member.Member.(string)
+		var job Job
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			b.dropMalformed(ctx, queue, raw, err)
+			continue
+		}
+		claimed = append(claimed, ClaimedJob{Job: job, Due: scoreTime(member.Score)})
+	}
+	return claimed, nil
+}
+
+func (b *RedisBackend) Head(ctx context.Context, queue string) (time.Time, bool, error) {
+	head, ok, err := b.redis.ZHead(ctx, queue)
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+	return scoreTime(head.Score), true, nil
+}
+
+func (b *RedisBackend) Jobs(ctx context.Context, queue string) ([]Job, error) {
+	members, err := b.redis.ZMembers(ctx, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, 0, len(members))
+	for _, member := range members {
+		var job Job
+		if err := json.Unmarshal([]byte(member), &job); err != nil {
+			b.dropMalformed(ctx, queue, member, err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (b *RedisBackend) dropMalformed(ctx context.Context, queue, member string, err error) {
+	b.logger.Error("Failed to unmarshal scheduled job", "error", err, "queue", queue, "member", member)
+	if err := b.redis.GetClient().ZRem(ctx, queue, member).Err(); err != nil {
+		b.logger.Error("Failed to drop malformed job", "error", err, "queue", queue)
+	}
+}
+
+func (b *RedisBackend) Publish(ctx context.Context, at time.Time) error {
+	if err := b.redis.Publish(ctx, WakeupChannel, strconv.FormatFloat(timeScore(at), 'f', -1, 64)); err != nil {
+		return fmt.Errorf("failed to publish wakeup: %w", err)
+	}
+	return nil
+}
+
+func (b *RedisBackend) Subscribe(ctx context.Context) (<-chan time.Time, func()) {
+	pubsub := b.redis.Subscribe(ctx, WakeupChannel)
+	wakeups := make(chan time.Time)
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			score, err := strconv.ParseFloat(msg.Payload, 64)
+			if err != nil {
+				continue
+			}
+			select {
+			case wakeups <- scoreTime(score):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return wakeups, func() { pubsub.Close() }
+}
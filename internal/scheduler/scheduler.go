@@ -0,0 +1,454 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/db"
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+	"github.com/MostafaSensei106/Riko-Chan/internal/services"
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+const (
+	MessagesQueue      = "scheduled_messages"
+	NotificationsQueue = "notifications"
+	DeadLetterQueue    = "dead_letter_messages"
+	DeletionsQueue     = "scheduled_deletions"
+	// Vacation jobs carry only a user ID
+	VacationStartsQueue = "vacation_starts"
+	VacationEndsQueue   = "vacation_ends"
+)
+
+const (
+	// claimLease is how long a claimed job stays invisible to other
+	// instances before it is considered abandoned. Jobs are claimed one at a
+	// time, since a delivery may wait on Telegram rate limits and a lease
+	// shared by a batch could run out before its last job is handled.
+	claimLease = 2 * time.Minute
+
+	// maxIdleWait bounds how long the scheduler sleeps when nothing is due,
+	// as a safety net for missed wakeups and expiring leases.
+	maxIdleWait = time.Minute
+)
+
+// Job is a unit of scheduled work on a queue.
+type Job struct {
+	MessageID uuid.UUID `json:"message_id"`
+	UserID    int64     `json:"user_id"`
+	// Offset tells reminders of the same message apart on NotificationsQueue
+	Offset time.Duration `json:"offset,omitempty"`
+}
+
+// ClaimedJob is a job taken off a queue together with the time it was due.
+type ClaimedJob struct {
+	Job Job
+	Due time.Time
+}
+
+// Backend stores jobs in time-ordered queues. Implementations must make
+// Claim safe to call from several scheduler instances at once.
+type Backend interface {
+	// Add puts job on queue at the given time, moving it if already present.
+	Add(ctx context.Context, queue string, job Job, at time.Time) error
+	// AddIfMissing puts job on queue only if it is not there yet.
+	AddIfMissing(ctx context.Context, queue string, job Job, at time.Time) (bool, error)
+	Remove(ctx context.Context, queue string, job Job) error
+	// Claim leases up to limit jobs due at or before now until leaseUntil.
+	// A job that is not removed before its lease expires becomes due again.
+	Claim(ctx context.Context, queue string, now, leaseUntil time.Time, limit int) ([]ClaimedJob, error)
+	// Head returns when the earliest job on queue is due.
+	Head(ctx context.Context, queue string) (time.Time, bool, error)
+	Jobs(ctx context.Context, queue string) ([]Job, error)
+	// Publish announces a newly scheduled time to other instances and
+	// Subscribe receives those announcements. Backends without cross-instance
+	// signalling may return a nil channel.
+	Publish(ctx context.Context, at time.Time) error
+	Subscribe(ctx context.Context) (<-chan time.Time, func())
+}
+
+// Scheduler fires scheduled messages and notifications from a Backend.
+type Scheduler struct {
+	backend        Backend
+	repo           *db.MessageRepository
+	deliveryRepo   *db.DeliveryRepository
+	userRepo       *db.UserRepository
+	messageService *services.MessageService
+	logger         *utils.Logger
+
+	wake    chan struct{}
+	mu      sync.Mutex
+	nextDue time.Time
+}
+
+func NewScheduler(backend Backend, repo *db.MessageRepository, deliveryRepo *db.DeliveryRepository, messageService *services.MessageService, logger *utils.Logger) *Scheduler {
+	return &Scheduler{
+		backend:        backend,
+		repo:           repo,
+		deliveryRepo:   deliveryRepo,
+		messageService: messageService,
+		logger:         logger,
+		wake:           make(chan struct{}, 1),
+	}
+}
+
+func (s *Scheduler) SetUserRepo(userRepo *db.UserRepository) {
+	s.userRepo = userRepo
+}
+
+// Start runs the scheduler loop. Instead of polling on a fixed interval it
+// sleeps until the earliest job is due and wakes early whenever a sooner job
+// is scheduled by this or any other instance.
+func (s *Scheduler) Start(ctx context.Context) {
+	wakeups, unsubscribe := s.backend.Subscribe(ctx)
+	defer unsubscribe()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	go s.runReconciler(ctx)
+
+	s.logger.Info("Scheduler started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Scheduler stopping...")
+			return
+		case <-timer.C:
+		case <-s.wake:
+		case at := <-wakeups:
+			if !s.isSooner(at) {
+				continue
+			}
+		}
+
+		if err := s.processScheduledMessages(ctx); err != nil {
+			s.logger.Error("Failed to process scheduled messages", "error", err)
+		}
+		if err := s.processNotifications(ctx); err != nil {
+			s.logger.Error("Failed to process notifications", "error", err)
+		}
+		if err := s.processDeletions(ctx); err != nil {
+			s.logger.Error("Failed to process deletions", "error", err)
+		}
+		if err := s.processVacations(ctx); err != nil {
+			s.logger.Error("Failed to process vacations", "error", err)
+		}
+
+		timer.Reset(s.untilNextDue(ctx))
+	}
+}
+
+// untilNextDue peeks the head of every queue and returns how long to sleep
+// until the earliest of them is due.
+func (s *Scheduler) untilNextDue(ctx context.Context) time.Duration {
+	now := time.Now()
+	next := now.Add(maxIdleWait)
+
+	for _, queue := range []string{MessagesQueue, NotificationsQueue, DeletionsQueue, VacationStartsQueue, VacationEndsQueue} {
+		due, ok, err := s.backend.Head(ctx, queue)
+		if err != nil {
+			s.logger.Error("Failed to peek schedule", "error", err, "queue", queue)
+			continue
+		}
+		if ok && due.Before(next) {
+			next = due
+		}
+	}
+
+	s.mu.Lock()
+	s.nextDue = next
+	s.mu.Unlock()
+
+	if next.Before(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+func (s *Scheduler) isSooner(t time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.nextDue.IsZero() || t.Before(s.nextDue)
+}
+
+// notifyScheduled wakes the local loop if a job was added ahead of the
+// current sleep deadline and tells the other instances about it.
+func (s *Scheduler) notifyScheduled(ctx context.Context, at time.Time) {
+	if s.isSooner(at) {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	if err := s.backend.Publish(ctx, at); err != nil {
+		s.logger.Error("Failed to publish scheduler wakeup", "error", err)
+	}
+}
+
+func (s *Scheduler) ScheduleMessage(ctx context.Context, message *models.Message) error {
+	job := Job{
+		MessageID: message.ID,
+		UserID:    message.UserID,
+	}
+
+	// A message with a delivery window goes out at its drawn time
+	deliverAt := message.DeliveryTime()
+	if err := s.backend.Add(ctx, MessagesQueue, job, deliverAt); err != nil {
+		return fmt.Errorf("failed to schedule message: %w", err)
+	}
+	s.notifyScheduled(ctx, deliverAt)
+
+	// Replace reminders left from an earlier scheduled time
+	if err := s.removeReminders(ctx, message.ID); err != nil {
+		s.logger.Error("Failed to cancel reminders", "error", err, "message_id", message.ID)
+	}
+	for reminder, at := range reminderJobs(message) {
+		if err := s.backend.Add(ctx, NotificationsQueue, reminder, at); err != nil {
+			s.logger.Error("Failed to schedule reminder", "error", err, "message_id", message.ID)
+			continue
+		}
+		s.notifyScheduled(ctx, at)
+	}
+
+	s.logger.Info("Message scheduled", "message_id", message.ID, "scheduled_time", message.ScheduledTime, "deliver_at", deliverAt)
+	return nil
+}
+
+func (s *Scheduler) CancelMessage(ctx context.Context, messageID uuid.UUID, userID int64) error {
+	job := Job{
+		MessageID: messageID,
+		UserID:    userID,
+	}
+
+	if err := s.backend.Remove(ctx, MessagesQueue, job); err != nil {
+		return fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+
+	if err := s.removeReminders(ctx, messageID); err != nil {
+		s.logger.Error("Failed to cancel reminders", "error", err)
+	}
+
+	if err := s.backend.Remove(ctx, DeadLetterQueue, job); err != nil {
+		s.logger.Error("Failed to remove dead-lettered message", "error", err)
+	}
+
+	s.logger.Info("Message cancelled", "message_id", messageID)
+	return nil
+}
+
+// RetryMessage moves a message so it is attempted again at the given time.
+// It replaces any claim lease the message currently holds.
+func (s *Scheduler) RetryMessage(ctx context.Context, message *models.Message, at time.Time) error {
+	job := Job{
+		MessageID: message.ID,
+		UserID:    message.UserID,
+	}
+
+	if err := s.backend.Add(ctx, MessagesQueue, job, at); err != nil {
+		return fmt.Errorf("failed to reschedule message: %w", err)
+	}
+	s.notifyScheduled(ctx, at)
+
+	s.logger.Info("Message rescheduled", "message_id", message.ID, "retry_at", at)
+	return nil
+}
+
+// DeadLetter takes a message out of the schedule and parks it on the
+// dead-letter queue, ordered by the time it failed.
+func (s *Scheduler) DeadLetter(ctx context.Context, message *models.Message) error {
+	job := Job{
+		MessageID: message.ID,
+		UserID:    message.UserID,
+	}
+
+	if err := s.backend.Remove(ctx, MessagesQueue, job); err != nil {
+		return fmt.Errorf("failed to unschedule message: %w", err)
+	}
+
+	if err := s.removeReminders(ctx, message.ID); err != nil {
+		s.logger.Error("Failed to cancel reminders", "error", err)
+	}
+
+	if err := s.backend.Add(ctx, DeadLetterQueue, job, time.Now()); err != nil {
+		return fmt.Errorf("failed to dead-letter message: %w", err)
+	}
+
+	s.logger.Info("Message dead-lettered", "message_id", message.ID)
+	return nil
+}
+
+// reminderJobs returns the reminders of message that are still ahead, with
+// the time each one is due. Reminders count back from the scheduled time, so
+// they come before a delivery window opens.
+func reminderJobs(message *models.Message) map[Job]time.Time {
+	jobs := make(map[Job]time.Time, len(message.Reminders))
+	for _, offset := range message.Reminders {
+		at := message.ScheduledTime.Add(-offset)
+		if offset <= 0 || !at.After(time.Now()) {
+			continue
+		}
+		jobs[Job{MessageID: message.ID, UserID: message.UserID, Offset: offset}] = at
+	}
+	return jobs
+}
+
+// removeReminders drops every queued reminder of a message, whatever offsets
+// it was scheduled with.
+func (s *Scheduler) removeReminders(ctx context.Context, messageID uuid.UUID) error {
+	queued, err := s.backend.Jobs(ctx, NotificationsQueue)
+	if err != nil {
+		return fmt.Errorf("failed to list reminders: %w", err)
+	}
+
+	for _, job := range queued {
+		if job.MessageID != messageID {
+			continue
+		}
+		if err := s.backend.Remove(ctx, NotificationsQueue, job); err != nil {
+			return fmt.Errorf("failed to remove reminder: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) RemoveDeadLetter(ctx context.Context, messageID uuid.UUID, userID int64) error {
+	job := Job{
+		MessageID: messageID,
+		UserID:    userID,
+	}
+
+	if err := s.backend.Remove(ctx, DeadLetterQueue, job); err != nil {
+		return fmt.Errorf("failed to remove dead-lettered message: %w", err)
+	}
+	return nil
+}
+
+func (s *Scheduler) ScheduleDeletion(ctx context.Context, delivery *models.Delivery) error {
+	if delivery.DeleteAt == nil {
+		return nil
+	}
+
+	job := Job{
+		MessageID: delivery.MessageID,
+		UserID:    delivery.UserID,
+	}
+
+	if err := s.backend.Add(ctx, DeletionsQueue, job, *delivery.DeleteAt); err != nil {
+		return fmt.Errorf("failed to schedule deletion: %w", err)
+	}
+	s.notifyScheduled(ctx, *delivery.DeleteAt)
+
+	s.logger.Info("Deletion scheduled", "message_id", delivery.MessageID, "delete_at", *delivery.DeleteAt)
+	return nil
+}
+
+func (s *Scheduler) ScheduleVacation(ctx context.Context, userID int64, from, until time.Time) error {
+	job := Job{UserID: userID}
+
+	if err := s.backend.Add(ctx, VacationStartsQueue, job, from); err != nil {
+		return fmt.Errorf("failed to schedule vacation start: %w", err)
+	}
+	if err := s.backend.Add(ctx, VacationEndsQueue, job, until); err != nil {
+		return fmt.Errorf("failed to schedule vacation end: %w", err)
+	}
+	s.notifyScheduled(ctx, from)
+
+	s.logger.Info("Vacation scheduled", "user_id", userID, "from", from, "until", until)
+	return nil
+}
+
+func (s *Scheduler) CancelVacation(ctx context.Context, userID int64) error {
+	job := Job{UserID: userID}
+
+	if err := s.backend.Remove(ctx, VacationStartsQueue, job); err != nil {
+		return fmt.Errorf("failed to cancel vacation start: %w", err)
+	}
+	if err := s.backend.Remove(ctx, VacationEndsQueue, job); err != nil {
+		return fmt.Errorf("failed to cancel vacation end: %w", err)
+	}
+	return nil
+}
+
+func (s *Scheduler) processScheduledMessages(ctx context.Context) error {
+	return s.processDue(ctx, MessagesQueue, func(job Job) error {
+		return s.messageService.SendScheduledMessage(ctx, job.MessageID)
+	})
+}
+
+func (s *Scheduler) processNotifications(ctx context.Context) error {
+	return s.processDue(ctx, NotificationsQueue, func(job Job) error {
+		return s.messageService.SendNotification(ctx, job.MessageID, job.Offset)
+	})
+}
+
+func (s *Scheduler) processDeletions(ctx context.Context) error {
+	return s.processDue(ctx, DeletionsQueue, func(job Job) error {
+		return s.messageService.DeleteDeliveredMessage(ctx, job.MessageID)
+	})
+}
+
+func (s *Scheduler) processVacations(ctx context.Context) error {
+	if err := s.processDue(ctx, VacationStartsQueue, func(job Job) error {
+		return s.messageService.StartVacation(ctx, job.UserID)
+	}); err != nil {
+		return err
+	}
+	return s.processDue(ctx, VacationEndsQueue, func(job Job) error {
+		return s.messageService.EndVacation(ctx, job.UserID)
+	})
+}
+
+// processDue claims due jobs of queue one at a time and hands them to
+// handle. A job is only removed once handled; if the worker dies in between,
+// the claim lease expires and another instance picks the job up again.
+func (s *Scheduler) processDue(ctx context.Context, queue string, handle func(Job) error) error {
+	for {
+		now := time.Now()
+		claimed, err := s.backend.Claim(ctx, queue, now, now.Add(claimLease), 1)
+		if err != nil {
+			return fmt.Errorf("failed to claim due jobs from %s: %w", queue, err)
+		}
+
+		for _, claim := range claimed {
+			job := claim.Job
+			if err := handle(job); err != nil {
+				switch {
+				case errors.Is(err, services.ErrDeliveryRescheduled):
+					// The job was already moved to its next attempt
+					continue
+				case errors.Is(err, services.ErrMessageBusy):
+					// Another worker is sending it; look again once the lease expires
+					s.logger.Info("Message is being delivered elsewhere", "queue", queue, "message_id", job.MessageID)
+					continue
+				case errors.Is(err, services.ErrMessageNotPending), errors.Is(err, services.ErrMessageDeadLettered),
+					errors.Is(err, services.ErrMessageMissed), errors.Is(err, services.ErrMessagePaused):
+					s.logger.Info("Dropping job for message that is no longer pending", "queue", queue, "message_id", job.MessageID)
+				default:
+					// Leave the lease in place so the job is retried once it expires
+					s.logger.Error("Failed to process scheduled job", "error", err, "queue", queue, "message_id", job.MessageID)
+					continue
+				}
+			} else {
+				lag := time.Since(claim.Due)
+				s.logger.Info("Scheduled job processed", "queue", queue, "message_id", job.MessageID,
+					"lag_ms", float64(lag)/float64(time.Millisecond))
+			}
+
+			if err := s.backend.Remove(ctx, queue, job); err != nil {
+				s.logger.Error("Failed to acknowledge scheduled job", "error", err, "queue", queue, "message_id", job.MessageID)
+			}
+		}
+
+		if len(claimed) == 0 {
+			return nil
+		}
+	}
+}
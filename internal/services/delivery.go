@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+// chatTarget identifies the chat a message is delivered to. Channels and
+// public groups may be addressed by "@username" instead of a numeric ID.
+type chatTarget struct {
+	chatID          int64
+	channelUsername string
+}
+
+func resolveChatTarget(message *models.Message) (chatTarget, error) {
+	switch {
+	case message.RecipientID != nil:
+		return chatTarget{chatID: *message.RecipientID}, nil
+	case message.GroupID != nil && *message.GroupID != "":
+		return parseChatTarget(*message.GroupID)
+	case message.ChannelID != nil && *message.ChannelID != "":
+		return parseChatTarget(*message.ChannelID)
+	default:
+		return chatTarget{}, fmt.Errorf("message has no recipient, group or channel")
+	}
+}
+
+func parseChatTarget(id string) (chatTarget, error) {
+	id = strings.TrimSpace(id)
+	if strings.HasPrefix(id, "@") {
+		return chatTarget{channelUsername: id}, nil
+	}
+
+	chatID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return chatTarget{}, fmt.Errorf("invalid chat id: %s", id)
+	}
+	return chatTarget{chatID: chatID}, nil
+}
+
+func (t chatTarget) apply(chat *tgbotapi.BaseChat, replyMarkup interface{}) {
+	chat.ChatID = t.chatID
+	chat.ChannelUsername = t.channelUsername
+	chat.ReplyMarkup = replyMarkup
+}
+
+// isSelfDelivery reports whether a message is delivered to its own owner.
+func isSelfDelivery(message *models.Message) bool {
+	return message.RecipientID != nil && *message.RecipientID == message.UserID
+}
+
+// buildDelivery converts a stored message into the bot API request that
+// delivers it to its target chat, with an optional reply markup attached.
+func buildDelivery(message *models.Message, replyMarkup interface{}) (tgbotapi.Chattable, error) {
+	target, err := resolveChatTarget(message)
+	if err != nil {
+		return nil, err
+	}
+
+	switch message.MessageType {
+	case models.MessageTypeText:
+		if message.Content == "" {
+			return nil, fmt.Errorf("text message has no content")
+		}
+		msg := tgbotapi.NewMessage(0, message.Content)
+		target.apply(&msg.BaseChat, replyMarkup)
+		return msg, nil
+	case models.MessageTypePhoto:
+		if message.MediaFileID == nil {
+			return nil, fmt.Errorf("photo message has no media file")
+		}
+		photo := tgbotapi.NewPhoto(0, tgbotapi.FileID(*message.MediaFileID))
+		photo.Caption = message.Content
+		target.apply(&photo.BaseChat, replyMarkup)
+		return photo, nil
+	case models.MessageTypeDocument:
+		if message.MediaFileID == nil {
+			return nil, fmt.Errorf("document message has no media file")
+		}
+		document := tgbotapi.NewDocument(0, tgbotapi.FileID(*message.MediaFileID))
+		document.Caption = message.Content
+		target.apply(&document.BaseChat, replyMarkup)
+		return document, nil
+	case models.MessageTypeAudio:
+		if message.MediaFileID == nil {
+			return nil, fmt.Errorf("audio message has no media file")
+		}
+		audio := tgbotapi.NewAudio(0, tgbotapi.FileID(*message.MediaFileID))
+		audio.Caption = message.Content
+		target.apply(&audio.BaseChat, replyMarkup)
+		return audio, nil
+	case models.MessageTypeLocation:
+		if message.Location == nil {
+			return nil, fmt.Errorf("location message has no location")
+		}
+		loc := message.Location
+		if loc.Title != "" && loc.Address != "" {
+			venue := tgbotapi.NewVenue(0, loc.Title, loc.Address, loc.Latitude, loc.Longitude)
+			target.apply(&venue.BaseChat, replyMarkup)
+			return venue, nil
+		}
+		location := tgbotapi.NewLocation(0, loc.Latitude, loc.Longitude)
+		target.apply(&location.BaseChat, replyMarkup)
+		return location, nil
+	default:
+		return nil, fmt.Errorf("unsupported message type: %s", message.MessageType)
+	}
+}
@@ -2,30 +2,70 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
-	"github.com/MostafaSensei106/Riko-Chan/internal/cache"
+	"github.com/MostafaSensei106/Riko-Chan/config"
+	"github.com/MostafaSensei106/Riko-Chan/internal/calendar"
 	"github.com/MostafaSensei106/Riko-Chan/internal/db"
+	"github.com/MostafaSensei106/Riko-Chan/internal/events"
 	"github.com/MostafaSensei106/Riko-Chan/internal/models"
 	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
 )
 
+// ErrMessageNotPending is returned when a scheduled job refers to a message
+// that has already been sent, cancelled, failed or deleted.
+var ErrMessageNotPending = errors.New("message is not in pending status")
+
+// ErrMessageBusy is returned when another worker is delivering the message
+// right now.
+var ErrMessageBusy = errors.New("message is being delivered")
+
+// deliveryLease is how long a worker may take to deliver a message before
+// another may try.
+const deliveryLease = 2 * time.Minute
+
+// Scheduler triggers delivery of messages at their scheduled time.
+type Scheduler interface {
+	ScheduleMessage(ctx context.Context, message *models.Message) error
+	CancelMessage(ctx context.Context, messageID uuid.UUID, userID int64) error
+	// RetryMessage moves a message to another attempt at the given time
+	RetryMessage(ctx context.Context, message *models.Message, at time.Time) error
+	DeadLetter(ctx context.Context, message *models.Message) error
+	RemoveDeadLetter(ctx context.Context, messageID uuid.UUID, userID int64) error
+	// ScheduleDeletion removes a delivered message from its chat at DeleteAt
+	ScheduleDeletion(ctx context.Context, delivery *models.Delivery) error
+	// ScheduleVacation starts and ends a user's vacation at the given times
+	ScheduleVacation(ctx context.Context, userID int64, from, until time.Time) error
+	CancelVacation(ctx context.Context, userID int64) error
+}
+
 type MessageService struct {
-	repo      *db.MessageRepository
-	userRepo  *db.UserRepository
-	redis     *cache.RedisClient
-	scheduler *cache.Scheduler
-	encryptor *utils.Encryptor
-	logger    *utils.Logger
+	repo         *db.MessageRepository
+	userRepo     *db.UserRepository
+	deliveryRepo *db.DeliveryRepository
+	seriesRepo   *db.SeriesRepository
+	calendars    *calendar.Registry
+	scheduler    Scheduler
+	sender       *TelegramSender
+	notifier     *NotificationService
+	events       *events.Bus
+	encryptor    *utils.Encryptor
+	logger       *utils.Logger
+
+	schedulerConfig config.SchedulerConfig
 }
 
-func NewMessageService(repo *db.MessageRepository, redis *cache.RedisClient, logger *utils.Logger) *MessageService {
+func NewMessageService(repo *db.MessageRepository, logger *utils.Logger) *MessageService {
 	return &MessageService{
 		repo:   repo,
-		redis:  redis,
 		logger: logger,
 	}
 }
@@ -34,14 +74,67 @@ func (s *MessageService) SetUserRepo(userRepo *db.UserRepository) {
 	s.userRepo = userRepo
 }
 
-func (s *MessageService) SetScheduler(scheduler *cache.Scheduler) {
+func (s *MessageService) SetDeliveryRepo(deliveryRepo *db.DeliveryRepository) {
+	s.deliveryRepo = deliveryRepo
+}
+
+func (s *MessageService) SetSeriesRepo(seriesRepo *db.SeriesRepository) {
+	s.seriesRepo = seriesRepo
+}
+
+func (s *MessageService) SetCalendars(calendars *calendar.Registry) {
+	s.calendars = calendars
+}
+
+func (s *MessageService) SetScheduler(scheduler Scheduler) {
 	s.scheduler = scheduler
 }
 
+func (s *MessageService) SetSender(sender *TelegramSender) {
+	s.sender = sender
+}
+
+func (s *MessageService) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
+func (s *MessageService) SetEventBus(bus *events.Bus) {
+	s.events = bus
+}
+
+func (s *MessageService) SetSchedulerConfig(cfg config.SchedulerConfig) {
+	s.schedulerConfig = cfg
+}
+
 func (s *MessageService) SetEncryptor(encryptor *utils.Encryptor) {
 	s.encryptor = encryptor
 }
 
+func (s *MessageService) publish(ctx context.Context, event events.Event) {
+	if s.events != nil {
+		s.events.Publish(ctx, event)
+	}
+}
+
+// getOwner returns the user who owns a message, or a default user when the
+// user repository is unavailable.
+func (s *MessageService) getOwner(userID int64) *models.User {
+	if s.userRepo != nil {
+		if user, err := s.userRepo.GetByID(userID); err == nil {
+			return user
+		}
+	}
+	return models.NewUser(userID, "")
+}
+
+// ownerLocation returns the timezone of a message owner, or UTC.
+func (s *MessageService) ownerLocation(userID int64) *time.Location {
+	if loc, err := time.LoadLocation(s.getOwner(userID).Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
 func (s *MessageService) CreateMessage(ctx context.Context, message *models.Message) error {
 	// Encrypt content if encryptor is available
 	if s.encryptor != nil {
@@ -52,6 +145,20 @@ func (s *MessageService) CreateMessage(ctx context.Context, message *models.Mess
 		message.Content = encryptedContent
 	}
 
+	// A new recurring message starts a series that owns its template
+	if s.seriesRepo != nil && message.RecurrenceType != models.RecurrenceNone && message.SeriesID == nil {
+		series := models.NewSeries(message)
+		if err := s.seriesRepo.Create(series); err != nil {
+			return fmt.Errorf("failed to create series: %w", err)
+		}
+		message.SeriesID = &series.ID
+
+		// The first occurrence obeys the workday policy like later ones
+		if at, ok := s.workdayTime(message); ok {
+			message.ScheduledTime = at
+		}
+	}
+
 	// Save to database
 	if err := s.repo.Create(message); err != nil {
 		return fmt.Errorf("failed to create message: %w", err)
@@ -64,6 +171,8 @@ func (s *MessageService) CreateMessage(ctx context.Context, message *models.Mess
 		}
 	}
 
+	s.publish(ctx, events.NewMessageCreated(message))
+
 	s.logger.Info("Message created", "message_id", message.ID, "user_id", message.UserID)
 	return nil
 }
@@ -87,6 +196,25 @@ func (s *MessageService) GetMessage(ctx context.Context, id uuid.UUID) (*models.
 	return message, nil
 }
 
+// shortIDPattern matches the start of a UUID as shown in /list
+var shortIDPattern = regexp.MustCompile(`^[0-9a-f-]{1,36}$`)
+
+// FindMessageByShortID resolves the start of a message or series ID, as
+// shown in /list, to one of the user's messages. A series ID stands for its
+// occurrence that is still to be delivered.
+func (s *MessageService) FindMessageByShortID(ctx context.Context, userID int64, shortID string) (uuid.UUID, error) {
+	shortID = strings.ToLower(strings.TrimSpace(shortID))
+	if !shortIDPattern.MatchString(shortID) {
+		return uuid.Nil, fmt.Errorf("message not found")
+	}
+
+	id, err := s.repo.FindByShortID(userID, shortID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("message not found: %w", err)
+	}
+	return id, nil
+}
+
 func (s *MessageService) GetUserMessages(ctx context.Context, userID int64, status models.MessageStatus, limit, offset int) ([]*models.Message, error) {
 	messages, err := s.repo.GetUserMessages(userID, status, limit, offset)
 	if err != nil {
@@ -131,6 +259,8 @@ func (s *MessageService) UpdateMessage(ctx context.Context, message *models.Mess
 		}
 	}
 
+	s.publish(ctx, events.NewMessageUpdated(message))
+
 	s.logger.Info("Message updated", "message_id", message.ID)
 	return nil
 }
@@ -148,6 +278,10 @@ func (s *MessageService) CancelMessage(ctx context.Context, id uuid.UUID, userID
 		}
 	}
 
+	if message, err := s.repo.GetByID(id); err == nil {
+		s.publish(ctx, events.NewMessageCancelled(message))
+	}
+
 	s.logger.Info("Message cancelled", "message_id", id)
 	return nil
 }
@@ -171,66 +305,217 @@ func (s *MessageService) DeleteMessage(ctx context.Context, id uuid.UUID, userID
 
 func (s *MessageService) SendScheduledMessage(ctx context.Context, messageID uuid.UUID) error {
 	message, err := s.GetMessage(ctx, messageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMessageNotPending
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get message: %w", err)
 	}
 
 	if message.Status != models.MessageStatusPending {
-		return fmt.Errorf("message is not in pending status")
+		return ErrMessageNotPending
 	}
 
-	// TODO: Implement actual message sending via Telegram API
-	// This would involve sending the message to the recipient via telegram bot API
+	// An earlier run may have delivered the message and then failed to mark
+	// it sent; finish that run instead of sending the message again
+	delivered, err := s.wasDelivered(message.ID)
+	if err != nil {
+		return err
+	}
+	if delivered {
+		return s.completeDelivery(ctx, message, false)
+	}
 
-	// Update status to sent
-	if err := s.repo.UpdateStatus(messageID, models.MessageStatusSent); err != nil {
-		return fmt.Errorf("failed to update message status: %w", err)
+	// Hold messages that come due while the owner is on vacation
+	if s.getOwner(message.UserID).OnVacation(time.Now()) {
+		if err := s.pause(ctx, message, true); err != nil {
+			return err
+		}
+		return ErrMessagePaused
 	}
 
-	// Handle recurrence
-	if message.RecurrenceType != models.RecurrenceNone {
-		if err := s.handleRecurrence(ctx, message); err != nil {
-			s.logger.Error("Failed to handle message recurrence", "error", err, "message_id", messageID)
+	// Apply the misfire policy when the message fires late, e.g. after
+	// downtime. Retries are late on purpose and are left alone.
+	catchUp := false
+	if lateness := time.Since(message.DeliveryTime()); message.Attempts == 0 && lateness > s.schedulerConfig.MisfireThreshold {
+		policy := s.misfirePolicy(message)
+		if handled, err := s.handleMisfire(ctx, message, policy, lateness); handled {
+			return err
+		}
+		catchUp = policy == models.MisfireCoalesce
+	}
+
+	// The scheduler and "send now" may both reach this point; only the one
+	// holding the claim delivers
+	now := time.Now()
+	claimed, err := s.repo.Claim(message.ID, now, now.Add(deliveryLease))
+	if err != nil {
+		return fmt.Errorf("failed to claim message: %w", err)
+	}
+	if !claimed {
+		return ErrMessageBusy
+	}
+
+	// Deliver via Telegram; the message only counts as sent once accepted
+	sent, err := s.deliver(ctx, message)
+	if err != nil {
+		if err := s.repo.ReleaseClaim(message.ID); err != nil {
+			s.logger.Error("Failed to release message claim", "error", err, "message_id", messageID)
 		}
+		return s.handleDeliveryFailure(ctx, message, err)
+	}
+
+	// The delivery record is what keeps a rerun from sending it twice
+	chatID := deliveredChatID(message, sent)
+	if err := s.recordDelivery(ctx, message, chatID, sent.MessageID); err != nil {
+		s.logger.Error("Failed to record delivery", "error", err, "message_id", messageID)
+	}
+	s.publish(ctx, events.NewMessageDelivered(message, chatID, sent.MessageID))
+
+	if err := s.completeDelivery(ctx, message, catchUp); err != nil {
+		return err
 	}
 
 	s.logger.Info("Scheduled message sent", "message_id", messageID)
 	return nil
 }
 
-func (s *MessageService) SendNotification(ctx context.Context, messageID uuid.UUID) error {
-	_, err := s.GetMessage(ctx, messageID)
+// wasDelivered reports whether a delivery was recorded for the message.
+func (s *MessageService) wasDelivered(id uuid.UUID) (bool, error) {
+	if s.deliveryRepo == nil {
+		return false, nil
+	}
+	_, err := s.deliveryRepo.GetByMessageID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get message: %w", err)
+		return false, fmt.Errorf("failed to get delivery: %w", err)
 	}
+	return true, nil
+}
 
-	// TODO: Implement notification sending via Telegram API
-	// Send a notification to the user about upcoming scheduled message
+// completeDelivery finishes a message Telegram has accepted: it moves a
+// recurring message on to its next occurrence and marks the message sent.
+// The next occurrence comes first and is only created once, so on an error
+// the job stays claimed and a later run finishes what is left.
+func (s *MessageService) completeDelivery(ctx context.Context, message *models.Message, catchUp bool) error {
+	if message.RecurrenceType != models.RecurrenceNone {
+		if err := s.handleRecurrence(ctx, message, catchUp); err != nil {
+			return fmt.Errorf("failed to handle message recurrence: %w", err)
+		}
+	}
 
-	s.logger.Info("Notification sent", "message_id", messageID)
-	return nil
+	return s.markSent(ctx, message.ID)
 }
 
-func (s *MessageService) handleRecurrence(ctx context.Context, message *models.Message) error {
+// statusWriteAttempts is how often marking a delivered message sent is tried
+// before the job is left for a later run.
+const statusWriteAttempts = 3
+
+func (s *MessageService) markSent(ctx context.Context, id uuid.UUID) error {
+	for attempt := 1; ; attempt++ {
+		err := s.repo.UpdateStatus(id, models.MessageStatusSent)
+		if err == nil {
+			return nil
+		}
+		if attempt == statusWriteAttempts {
+			return fmt.Errorf("failed to update message status: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to update message status: %w", err)
+		case <-time.After(time.Duration(attempt) * time.Second):
+		}
+	}
+}
+
+func (s *MessageService) deliver(ctx context.Context, message *models.Message) (tgbotapi.Message, error) {
+	if s.sender == nil {
+		return tgbotapi.Message{}, fmt.Errorf("no telegram sender configured")
+	}
+
+	// Reminders to self can be snoozed straight from the delivered message
+	var replyMarkup interface{}
+	if isSelfDelivery(message) {
+		replyMarkup = snoozeKeyboard(message, s.getOwner(message.UserID).Language)
+	}
+
+	request, err := buildDelivery(message, replyMarkup)
+	if err != nil {
+		return tgbotapi.Message{}, fmt.Errorf("%w: %v", errInvalidDelivery, err)
+	}
+
+	sent, err := s.sender.Send(ctx, request)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	s.logger.Info("Message delivered", "message_id", message.ID, "telegram_message_id", sent.MessageID)
+	return sent, nil
+}
+
+// handleRecurrence creates the next occurrence of a recurring message. With
+// catchUp set, occurrences that are already in the past are skipped over.
+func (s *MessageService) handleRecurrence(ctx context.Context, message *models.Message, catchUp bool) error {
+	// Later occurrences follow the series template, not edits made to this one
+	template, err := s.seriesTemplate(message)
+	if err != nil {
+		return err
+	}
+	if template == nil {
+		return nil
+	}
+
+	// The series already moved on, e.g. when a dead-lettered occurrence is
+	// retried by hand
+	if template.SeriesID != nil {
+		open, err := s.repo.GetOpenBySeries(*template.SeriesID)
+		if err != nil {
+			return fmt.Errorf("failed to get series occurrences: %w", err)
+		}
+		for _, occurrence := range open {
+			if occurrence.ID != message.ID {
+				return nil
+			}
+		}
+	}
+
 	// Check if we've reached the max recurrences
-	if message.MaxRecurrences != nil && message.RecurrenceCount >= *message.MaxRecurrences {
+	if template.MaxRecurrences != nil && message.RecurrenceCount >= *template.MaxRecurrences {
 		return nil
 	}
 
 	// Create next occurrence
-	nextMessage := *message
+	nextMessage := *template
 	nextMessage.ID = uuid.New()
 	nextMessage.RecurrenceCount++
 	nextMessage.Status = models.MessageStatusPending
+	nextMessage.Attempts = 0
+	nextMessage.FailureReason = nil
 	nextMessage.CreatedAt = time.Now()
 	nextMessage.UpdatedAt = time.Now()
 
 	// Calculate next scheduled time
-	nextMessage.ScheduledTime = utils.GetNextRecurrenceTime(
-		message.ScheduledTime,
-		string(message.RecurrenceType),
-		1,
-	)
+	rc := s.recurrenceContext(template)
+	next, ok := nextOccurrence(template, rc, message.ScheduledTime)
+	if !ok {
+		return nil
+	}
+	nextMessage.ScheduledTime = next
+	for catchUp && !nextMessage.ScheduledTime.After(time.Now()) {
+		next, ok := nextOccurrence(template, rc, nextMessage.ScheduledTime)
+		if !ok {
+			break
+		}
+		nextMessage.ScheduledTime = next
+		nextMessage.RecurrenceCount++
+	}
+
+	if template.MaxRecurrences != nil && nextMessage.RecurrenceCount > *template.MaxRecurrences {
+		return nil
+	}
 
 	// Create the recurring message
 	if err := s.CreateMessage(ctx, &nextMessage); err != nil {
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+// ErrMessageMissed is returned when a late message was not delivered
+// because of its misfire policy or because it went stale.
+var ErrMessageMissed = errors.New("message missed its delivery window")
+
+// misfirePolicy returns the policy of the message, falling back to the
+// owner's default.
+func (s *MessageService) misfirePolicy(message *models.Message) models.MisfirePolicy {
+	if message.MisfirePolicy != nil && message.MisfirePolicy.IsValid() {
+		return *message.MisfirePolicy
+	}
+	if owner := s.getOwner(message.UserID); owner.MisfirePolicy.IsValid() {
+		return owner.MisfirePolicy
+	}
+	return models.MisfireFireNow
+}
+
+// SetMisfirePolicy overrides the misfire policy of a single message. A nil
+// policy makes the message follow the owner's default again.
+func (s *MessageService) SetMisfirePolicy(ctx context.Context, id uuid.UUID, userID int64, policy *models.MisfirePolicy) error {
+	message, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+	if message.UserID != userID {
+		return fmt.Errorf("message does not belong to user")
+	}
+
+	if err := s.repo.UpdateMisfirePolicy(id, policy); err != nil {
+		return fmt.Errorf("failed to update misfire policy: %w", err)
+	}
+	if err := s.updateSeriesSetting(message, "misfire_policy", policy); err != nil {
+		return err
+	}
+
+	s.logger.Info("Misfire policy updated", "message_id", id, "policy", policy)
+	return nil
+}
+
+// handleMisfire applies the misfire policy to a message that fired late and
+// reports whether it took care of the message instead of delivering it.
+func (s *MessageService) handleMisfire(ctx context.Context, message *models.Message, policy models.MisfirePolicy, lateness time.Duration) (bool, error) {
+	if message.RecurrenceType == models.RecurrenceNone {
+		stale := s.schedulerConfig.StaleAfter > 0 && lateness > s.schedulerConfig.StaleAfter
+		if policy != models.MisfireSkip && !stale {
+			return false, nil
+		}
+
+		if err := s.repo.UpdateStatus(message.ID, models.MessageStatusMissed); err != nil {
+			return true, fmt.Errorf("failed to mark message as missed: %w", err)
+		}
+		s.notifyMessageMissed(message, lateness)
+
+		s.logger.Warn("Message missed", "message_id", message.ID, "lateness", lateness, "policy", policy)
+		return true, ErrMessageMissed
+	}
+
+	if policy != models.MisfireSkip {
+		return false, nil
+	}
+
+	// Drop the missed occurrence and continue the series from the next
+	// occurrence that is still ahead
+	if err := s.repo.UpdateStatus(message.ID, models.MessageStatusMissed); err != nil {
+		return true, fmt.Errorf("failed to mark message as missed: %w", err)
+	}
+	if err := s.handleRecurrence(ctx, message, true); err != nil {
+		s.logger.Error("Failed to handle message recurrence", "error", err, "message_id", message.ID)
+	}
+
+	s.logger.Warn("Recurring occurrence skipped", "message_id", message.ID, "lateness", lateness)
+	return true, ErrMessageMissed
+}
+
+func (s *MessageService) notifyMessageMissed(message *models.Message, lateness time.Duration) {
+	if s.notifier == nil {
+		return
+	}
+
+	owner := s.getOwner(message.UserID)
+	scheduledTime := message.ScheduledTime
+	if loc, err := time.LoadLocation(owner.Timezone); err == nil {
+		scheduledTime = scheduledTime.In(loc)
+	}
+
+	preview := utils.Truncate(message.Content, 50)
+
+	text := fmt.Sprintf(getText("message_missed", owner.Language),
+		scheduledTime.Format("2006-01-02 15:04"), utils.FormatDuration(lateness), preview, message.ID.String()[:8])
+	if err := s.notifier.SendNotification(message.UserID, text); err != nil {
+		s.logger.Error("Failed to notify owner about missed message", "error", err, "message_id", message.ID)
+	}
+}
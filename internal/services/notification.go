@@ -1,12 +1,18 @@
 package services
 
 import (
+	"context"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
 	"github.com/MostafaSensei106/Riko-Chan/config"
 	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
 )
 
 type NotificationService struct {
 	config *config.Config
+	sender *TelegramSender
 	logger *utils.Logger
 }
 
@@ -17,8 +23,31 @@ func NewNotificationService(cfg *config.Config, logger *utils.Logger) *Notificat
 	}
 }
 
+func (s *NotificationService) SetSender(sender *TelegramSender) {
+	s.sender = sender
+}
+
 func (s *NotificationService) SendNotification(userID int64, message string) error {
-	// TODO: Implement notification sending via Telegram API
-	s.logger.Info("Notification sent", "user_id", userID, "message", message)
+	return s.send(tgbotapi.NewMessage(userID, message))
+}
+
+// SendNotificationWithKeyboard sends a notification with inline actions the
+// user can tap to respond.
+func (s *NotificationService) SendNotificationWithKeyboard(userID int64, message string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	msg := tgbotapi.NewMessage(userID, message)
+	msg.ReplyMarkup = keyboard
+	return s.send(msg)
+}
+
+func (s *NotificationService) send(msg tgbotapi.MessageConfig) error {
+	if s.sender == nil {
+		return fmt.Errorf("no telegram sender configured")
+	}
+
+	if _, err := s.sender.Send(context.Background(), msg); err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	s.logger.Info("Notification sent", "user_id", msg.ChatID)
 	return nil
 }
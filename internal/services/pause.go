@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+// ErrMessagePaused is returned when a message came due while paused, e.g.
+// because its owner is on vacation, and was taken out of the schedule.
+var ErrMessagePaused = errors.New("message is paused")
+
+// PauseMessage holds a pending message until it is resumed.
+func (s *MessageService) PauseMessage(ctx context.Context, id uuid.UUID, userID int64) error {
+	message, err := s.getOwnedMessage(id, userID)
+	if err != nil {
+		return err
+	}
+
+	// A message held by vacation stays paused once the vacation ends
+	if message.Status == models.MessageStatusPaused && message.HeldForVacation {
+		if err := s.repo.Pause(id, false); err != nil {
+			return fmt.Errorf("failed to pause message: %w", err)
+		}
+		return nil
+	}
+	if message.Status != models.MessageStatusPending {
+		return ErrMessageNotPending
+	}
+
+	return s.pause(ctx, message, false)
+}
+
+// ResumeMessage puts a paused message back in the schedule and returns it
+// with its new scheduled time.
+func (s *MessageService) ResumeMessage(ctx context.Context, id uuid.UUID, userID int64) (*models.Message, error) {
+	message, err := s.getOwnedMessage(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if message.Status != models.MessageStatusPaused {
+		return nil, ErrMessageNotPending
+	}
+
+	if err := s.resume(ctx, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (s *MessageService) pause(ctx context.Context, message *models.Message, heldForVacation bool) error {
+	if err := s.repo.Pause(message.ID, heldForVacation); err != nil {
+		return fmt.Errorf("failed to pause message: %w", err)
+	}
+	message.Status = models.MessageStatusPaused
+	message.HeldForVacation = heldForVacation
+
+	if s.scheduler != nil {
+		if err := s.scheduler.CancelMessage(ctx, message.ID, message.UserID); err != nil {
+			s.logger.Error("Failed to unschedule paused message", "error", err, "message_id", message.ID)
+		}
+	}
+
+	s.logger.Info("Message paused", "message_id", message.ID, "held_for_vacation", heldForVacation)
+	return nil
+}
+
+// resume schedules a paused message again. Occurrences of a recurring message
+// that passed while it was paused are skipped rather than replayed, and an
+// overdue one-off message goes out right away.
+func (s *MessageService) resume(ctx context.Context, message *models.Message) error {
+	now := time.Now()
+	scheduledTime, count := message.ScheduledTime, message.RecurrenceCount
+
+	if message.RecurrenceType != models.RecurrenceNone {
+		rc := s.recurrenceContext(message)
+		ended := false
+		for !scheduledTime.After(now) {
+			next, ok := nextOccurrence(message, rc, scheduledTime)
+			if !ok {
+				ended = true
+				break
+			}
+			scheduledTime = next
+			count++
+		}
+
+		// The series ran out while paused, by count or because its cron
+		// expression or rule has no occurrences left
+		if ended || (message.MaxRecurrences != nil && count > *message.MaxRecurrences) {
+			if err := s.repo.UpdateStatus(message.ID, models.MessageStatusMissed); err != nil {
+				return fmt.Errorf("failed to update message status: %w", err)
+			}
+			message.Status = models.MessageStatusMissed
+			return nil
+		}
+	}
+	if scheduledTime.Before(now) {
+		scheduledTime = now
+	}
+
+	if err := s.repo.Resume(message.ID, scheduledTime, count); err != nil {
+		return fmt.Errorf("failed to resume message: %w", err)
+	}
+	message.Status = models.MessageStatusPending
+	message.HeldForVacation = false
+	message.ScheduledTime = scheduledTime
+	message.RecurrenceCount = count
+
+	if s.scheduler != nil {
+		if err := s.scheduler.ScheduleMessage(ctx, message); err != nil {
+			return fmt.Errorf("failed to reschedule message: %w", err)
+		}
+	}
+
+	s.logger.Info("Message resumed", "message_id", message.ID, "scheduled_time", scheduledTime)
+	return nil
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/calendar"
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+// maxPreviewOccurrences bounds how far ahead a schedule can be previewed
+const maxPreviewOccurrences = 20
+
+// Occurrence is one upcoming delivery of a message with the reminders that
+// lead up to it. A message with a delivery window goes out within Window
+// after At.
+type Occurrence struct {
+	At        time.Time
+	Window    time.Duration
+	Reminders []time.Time
+}
+
+// maxWorkdayShift bounds the search for a working day, in days
+const maxWorkdayShift = 366
+
+// recurrenceContext is what computing the occurrences of a message needs
+// beyond the message itself.
+type recurrenceContext struct {
+	// start is the first occurrence of the series; fixed recurrences keep
+	// its wall-clock time
+	start time.Time
+	// loc is the owner's timezone
+	loc  *time.Location
+	work calendar.WorkSchedule
+}
+
+func (s *MessageService) recurrenceContext(message *models.Message) recurrenceContext {
+	rc := recurrenceContext{
+		start: message.ScheduledTime,
+		loc:   s.ownerLocation(message.UserID),
+	}
+	if s.seriesRepo != nil && message.SeriesID != nil {
+		if series, err := s.seriesRepo.GetByID(*message.SeriesID); err == nil {
+			rc.start = series.StartTime
+		}
+	}
+	if s.calendars != nil {
+		owner := s.getOwner(message.UserID)
+		rc.work = s.calendars.Schedule(stringValue(owner.Weekend), stringValue(owner.HolidayCalendar))
+	}
+	return rc
+}
+
+// nextOccurrence returns the occurrence of a recurring message that follows
+// the one at from. Occurrences on a non-working day follow the message's
+// workday policy. It reports false when the rule yields no later time.
+func nextOccurrence(message *models.Message, rc recurrenceContext, from time.Time) (time.Time, bool) {
+	var policy models.WorkdayPolicy
+	if message.WorkdayPolicy != nil {
+		policy = *message.WorkdayPolicy
+	}
+	if message.RecurrenceType == models.RecurrenceWorkdays {
+		policy = models.WorkdaySkip
+	}
+
+	// Moving an occurrence back can land on or before from, so keep
+	// stepping through the rule until an occurrence lies ahead
+	candidate := from
+	for i := 0; i < maxWorkdayShift; i++ {
+		next, ok := nextScheduled(message, rc, candidate)
+		if !ok {
+			return time.Time{}, false
+		}
+		candidate = next
+
+		if at, ok := rc.onWorkingDay(next, policy); ok && at.After(from) {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextScheduled returns the occurrence the recurrence rule itself yields
+// after from, on the wall clock of the owner's timezone.
+func nextScheduled(message *models.Message, rc recurrenceContext, from time.Time) (time.Time, bool) {
+	switch message.RecurrenceType {
+	case models.RecurrenceNone:
+		return time.Time{}, false
+	case models.RecurrenceCron:
+		if message.CronExpression == nil {
+			return time.Time{}, false
+		}
+		schedule, err := utils.ParseCron(*message.CronExpression)
+		if err != nil {
+			return time.Time{}, false
+		}
+		next := schedule.Next(from.In(rc.loc))
+		return next, !next.IsZero()
+	case models.RecurrenceRRule:
+		if message.RecurrenceRule == nil {
+			return time.Time{}, false
+		}
+		recurrence, err := utils.ParseRecurrence(*message.RecurrenceRule, rc.start, rc.loc)
+		if err != nil {
+			return time.Time{}, false
+		}
+		next := recurrence.Next(from, rc.loc)
+		return next, !next.IsZero()
+	case models.RecurrenceWorkdays:
+		next := utils.NextRecurrence(rc.start, string(models.RecurrenceDaily), 1, from, rc.loc)
+		return next, !next.IsZero()
+	}
+
+	next := utils.NextRecurrence(rc.start, string(message.RecurrenceType), message.Interval(), from, rc.loc)
+	return next, !next.IsZero()
+}
+
+// onWorkingDay applies a workday policy to t. It reports false when the
+// occurrence is skipped.
+func (rc recurrenceContext) onWorkingDay(t time.Time, policy models.WorkdayPolicy) (time.Time, bool) {
+	local := t.In(rc.loc)
+	if policy == "" || rc.work.IsWorkingDay(local.Year(), local.Month(), local.Day()) {
+		return t, true
+	}
+
+	step := 1
+	switch policy {
+	case models.WorkdayPrevious:
+		step = -1
+	case models.WorkdayNext:
+	default:
+		return time.Time{}, false
+	}
+
+	// Move by whole days, keeping the wall-clock time
+	for i := 1; i <= maxWorkdayShift; i++ {
+		day := local.AddDate(0, 0, i*step)
+		if rc.work.IsWorkingDay(day.Year(), day.Month(), day.Day()) {
+			return utils.LocalTime(day.Year(), day.Month(), day.Day(), local.Hour(), local.Minute(), local.Second(), rc.loc), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// PreviewOccurrences lists the next n deliveries of a pending or paused
+// message, stopping early when the series runs out.
+func (s *MessageService) PreviewOccurrences(ctx context.Context, id uuid.UUID, userID int64, n int) ([]Occurrence, error) {
+	message, err := s.getOwnedMessage(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if message.Status != models.MessageStatusPending && message.Status != models.MessageStatusPaused {
+		return nil, ErrMessageNotPending
+	}
+
+	if n <= 0 || n > maxPreviewOccurrences {
+		n = maxPreviewOccurrences
+	}
+
+	return previewOccurrences(message, s.recurrenceContext(message), n, time.Now()), nil
+}
+
+func previewOccurrences(message *models.Message, rc recurrenceContext, n int, now time.Time) []Occurrence {
+	occurrences := make([]Occurrence, 0, n)
+	at, count := message.ScheduledTime, message.RecurrenceCount
+
+	for len(occurrences) < n {
+		if message.MaxRecurrences != nil && count > *message.MaxRecurrences {
+			break
+		}
+
+		if at.After(now) || len(occurrences) > 0 {
+			occurrence := Occurrence{At: at}
+			if message.DeliveryWindow != nil {
+				occurrence.Window = *message.DeliveryWindow
+			}
+			for _, offset := range message.Reminders {
+				if reminder := at.Add(-offset); reminder.After(now) {
+					occurrence.Reminders = append(occurrence.Reminders, reminder)
+				}
+			}
+			sort.Slice(occurrence.Reminders, func(i, j int) bool {
+				return occurrence.Reminders[i].Before(occurrence.Reminders[j])
+			})
+			occurrences = append(occurrences, occurrence)
+		} else if message.Status == models.MessageStatusPending || message.RecurrenceType == models.RecurrenceNone {
+			// Overdue: a pending message is about to go out, and a paused
+			// one-off message goes out as soon as it is resumed
+			occurrences = append(occurrences, Occurrence{At: now})
+		}
+
+		next, ok := nextOccurrence(message, rc, at)
+		if !ok {
+			break
+		}
+		at = next
+		count++
+	}
+
+	if len(occurrences) == 0 {
+		return nil
+	}
+	return occurrences
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/events"
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+// ReminderPostpone is how much later the postpone button on a reminder moves
+// the message.
+const ReminderPostpone = time.Hour
+
+// SetReminders replaces the reminder offsets of a message and reschedules
+// its reminders. An empty list turns reminders off.
+func (s *MessageService) SetReminders(ctx context.Context, id uuid.UUID, userID int64, reminders models.Reminders) error {
+	message, err := s.getOwnedMessage(id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateReminders(id, reminders); err != nil {
+		return fmt.Errorf("failed to update reminders: %w", err)
+	}
+	if err := s.updateSeriesSetting(message, "reminders", reminders); err != nil {
+		return err
+	}
+	message.Reminders = reminders
+
+	if s.scheduler != nil && message.Status == models.MessageStatusPending {
+		if err := s.scheduler.ScheduleMessage(ctx, message); err != nil {
+			return fmt.Errorf("failed to reschedule reminders: %w", err)
+		}
+	}
+
+	s.logger.Info("Reminders updated", "message_id", id, "reminders", len(reminders))
+	return nil
+}
+
+// SendNotification reminds the owner of a message that is about to go out,
+// with buttons to send it now, postpone it or cancel it.
+func (s *MessageService) SendNotification(ctx context.Context, messageID uuid.UUID, offset time.Duration) error {
+	message, err := s.GetMessage(ctx, messageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMessageNotPending
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+
+	// The reminder is stale if the message moved on or the offset was removed
+	if message.Status != models.MessageStatusPending || !message.Reminders.Contains(offset) {
+		return ErrMessageNotPending
+	}
+
+	if s.notifier == nil {
+		return fmt.Errorf("no notification service configured")
+	}
+
+	owner := s.getOwner(message.UserID)
+	scheduledTime := message.ScheduledTime
+	if loc, err := time.LoadLocation(owner.Timezone); err == nil {
+		scheduledTime = scheduledTime.In(loc)
+	}
+
+	preview := utils.Truncate(message.Content, 50)
+
+	id := message.ID.String()
+	text := fmt.Sprintf(getText("reminder", owner.Language),
+		utils.FormatDuration(time.Until(message.ScheduledTime).Round(time.Minute)),
+		scheduledTime.Format("2006-01-02 15:04"), preview, id[:8])
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(getText("send_now", owner.Language), "sendnow_"+id),
+			tgbotapi.NewInlineKeyboardButtonData(getText("postpone", owner.Language), "postpone_"+id),
+			tgbotapi.NewInlineKeyboardButtonData(getText("cancel", owner.Language), "cancel_"+id),
+		),
+	)
+
+	if err := s.notifier.SendNotificationWithKeyboard(message.UserID, text, keyboard); err != nil {
+		return fmt.Errorf("failed to send reminder: %w", err)
+	}
+
+	s.publish(ctx, events.NewNotificationSent(message, offset))
+
+	s.logger.Info("Reminder sent", "message_id", messageID, "offset", offset)
+	return nil
+}
+
+// SendMessageNow delivers a pending message right away. Recurring messages
+// keep their original schedule for the next occurrence. If the scheduler is
+// delivering the message at the same moment, ErrMessageBusy is returned.
+func (s *MessageService) SendMessageNow(ctx context.Context, id uuid.UUID, userID int64) error {
+	message, err := s.getOwnedMessage(id, userID)
+	if err != nil {
+		return err
+	}
+	if message.Status != models.MessageStatusPending {
+		return ErrMessageNotPending
+	}
+
+	if err := s.SendScheduledMessage(ctx, id); err != nil {
+		return err
+	}
+
+	// The queued job would only be acked as not pending; drop it now
+	if s.scheduler != nil {
+		if err := s.scheduler.CancelMessage(ctx, id, userID); err != nil {
+			s.logger.Error("Failed to unschedule message", "error", err, "message_id", id)
+		}
+	}
+	return nil
+}
+
+// PostponeMessage moves a pending message later by the given delay and
+// returns its new scheduled time.
+func (s *MessageService) PostponeMessage(ctx context.Context, id uuid.UUID, userID int64, delay time.Duration) (time.Time, error) {
+	message, err := s.getOwnedMessage(id, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if message.Status != models.MessageStatusPending {
+		return time.Time{}, ErrMessageNotPending
+	}
+
+	message.ScheduledTime = message.ScheduledTime.Add(delay)
+	if err := s.repo.UpdateScheduledTime(id, message.ScheduledTime); err != nil {
+		return time.Time{}, fmt.Errorf("failed to postpone message: %w", err)
+	}
+
+	if s.scheduler != nil {
+		if err := s.scheduler.ScheduleMessage(ctx, message); err != nil {
+			return time.Time{}, fmt.Errorf("failed to reschedule message: %w", err)
+		}
+	}
+
+	s.logger.Info("Message postponed", "message_id", id, "scheduled_time", message.ScheduledTime)
+	return message.ScheduledTime, nil
+}
+
+func (s *MessageService) getOwnedMessage(id uuid.UUID, userID int64) (*models.Message, error) {
+	message, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	if message.UserID != userID {
+		return nil, fmt.Errorf("message does not belong to user")
+	}
+	return message, nil
+}
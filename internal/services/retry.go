@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/events"
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+const (
+	maxDeliveryAttempts = 5
+	retryBaseDelay      = 30 * time.Second
+	retryMaxDelay       = time.Hour
+)
+
+var (
+	// ErrDeliveryRescheduled is returned when a failed delivery has been
+	// pushed back in the schedule for another attempt.
+	ErrDeliveryRescheduled = errors.New("delivery rescheduled for retry")
+
+	// ErrMessageDeadLettered is returned when a delivery failed for good and
+	// the message was moved to the dead-letter set.
+	ErrMessageDeadLettered = errors.New("message moved to dead-letter set")
+
+	// ErrMessageNotFound is returned when a retry names a message that does
+	// not exist or belongs to someone else.
+	ErrMessageNotFound = errors.New("message not found")
+
+	// ErrMessageNotFailed is returned when a retry names a message that is
+	// not in the dead-letter set.
+	ErrMessageNotFailed = errors.New("message is not in failed status")
+
+	errInvalidDelivery = errors.New("invalid delivery")
+)
+
+// retryDelay returns the exponential backoff before the given attempt.
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// isPermanentDeliveryError reports whether retrying cannot help, e.g. the
+// chat does not exist or the bot was blocked.
+func isPermanentDeliveryError(err error) bool {
+	if errors.Is(err, errInvalidDelivery) {
+		return true
+	}
+
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == 400 || apiErr.Code == 403
+	}
+	return false
+}
+
+// handleDeliveryFailure records a failed attempt and either reschedules the
+// message with backoff or, once attempts are exhausted, dead-letters it,
+// tells the owner and moves a recurring message on to its next occurrence.
+func (s *MessageService) handleDeliveryFailure(ctx context.Context, message *models.Message, deliveryErr error) error {
+	attempts := message.Attempts + 1
+	reason := deliveryErr.Error()
+
+	if attempts < maxDeliveryAttempts && !isPermanentDeliveryError(deliveryErr) && s.scheduler != nil {
+		if err := s.repo.RecordFailedAttempt(message.ID, attempts, reason); err != nil {
+			return fmt.Errorf("failed to record delivery attempt: %w", err)
+		}
+
+		delay := retryDelay(attempts)
+		var apiErr *tgbotapi.Error
+		if errors.As(deliveryErr, &apiErr) && apiErr.RetryAfter > 0 {
+			if retryAfter := time.Duration(apiErr.RetryAfter) * time.Second; retryAfter > delay {
+				delay = retryAfter
+			}
+		}
+
+		retryAt := time.Now().Add(delay)
+		if err := s.scheduler.RetryMessage(ctx, message, retryAt); err != nil {
+			return fmt.Errorf("failed to reschedule message: %w", err)
+		}
+
+		s.logger.Warn("Delivery failed, retrying", "error", deliveryErr, "message_id", message.ID,
+			"attempt", attempts, "retry_at", retryAt)
+		return fmt.Errorf("%w: %v", ErrDeliveryRescheduled, deliveryErr)
+	}
+
+	if err := s.repo.RecordFailedAttempt(message.ID, attempts, reason); err != nil {
+		s.logger.Error("Failed to record delivery attempt", "error", err, "message_id", message.ID)
+	}
+	if err := s.repo.MarkFailed(message.ID, reason); err != nil {
+		return fmt.Errorf("failed to mark message as failed: %w", err)
+	}
+
+	if s.scheduler != nil {
+		if err := s.scheduler.DeadLetter(ctx, message); err != nil {
+			s.logger.Error("Failed to dead-letter message", "error", err, "message_id", message.ID)
+		}
+	}
+
+	s.notifyDeliveryFailed(message, attempts, reason)
+	s.publish(ctx, events.NewMessageFailed(message, attempts, reason))
+
+	// One failed occurrence does not end the series
+	if message.RecurrenceType != models.RecurrenceNone {
+		if err := s.handleRecurrence(ctx, message, true); err != nil {
+			s.logger.Error("Failed to handle message recurrence", "error", err, "message_id", message.ID)
+		}
+	}
+
+	s.logger.Error("Delivery failed permanently", "error", deliveryErr, "message_id", message.ID, "attempts", attempts)
+	return fmt.Errorf("%w: %v", ErrMessageDeadLettered, deliveryErr)
+}
+
+func (s *MessageService) notifyDeliveryFailed(message *models.Message, attempts int, reason string) {
+	if s.notifier == nil {
+		return
+	}
+
+	language := s.getOwner(message.UserID).Language
+
+	preview := utils.Truncate(message.Content, 50)
+
+	text := fmt.Sprintf(getText("delivery_failed", language), attempts, preview, reason, message.ID.String()[:8])
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(getText("retry_delivery", language), "retry_"+message.ID.String()),
+		),
+	)
+
+	if err := s.notifier.SendNotificationWithKeyboard(message.UserID, text, keyboard); err != nil {
+		s.logger.Error("Failed to notify owner about failed delivery", "error", err, "message_id", message.ID)
+	}
+}
+
+// RetryFailedMessage takes a dead-lettered message back out of the
+// dead-letter set and reschedules it for right now.
+func (s *MessageService) RetryFailedMessage(ctx context.Context, id uuid.UUID, userID int64) error {
+	message, err := s.repo.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMessageNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+
+	if message.UserID != userID {
+		return ErrMessageNotFound
+	}
+	if message.Status != models.MessageStatusFailed {
+		return ErrMessageNotFailed
+	}
+
+	now := time.Now()
+	if err := s.repo.ResetForRetry(id, now); err != nil {
+		return fmt.Errorf("failed to reset message: %w", err)
+	}
+	message.ScheduledTime = now
+	message.Status = models.MessageStatusPending
+	message.Attempts = 0
+	message.FailureReason = nil
+
+	if s.scheduler != nil {
+		if err := s.scheduler.RemoveDeadLetter(ctx, id, userID); err != nil {
+			s.logger.Error("Failed to remove message from dead-letter set", "error", err, "message_id", id)
+		}
+		if err := s.scheduler.RetryMessage(ctx, message, now); err != nil {
+			return fmt.Errorf("failed to reschedule message: %w", err)
+		}
+	}
+
+	s.logger.Info("Failed message retried", "message_id", id)
+	return nil
+}
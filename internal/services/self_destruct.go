@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+// SetSelfDestruct makes a message delete itself from the chat the given time
+// after delivery. A nil delay keeps the delivered message.
+func (s *MessageService) SetSelfDestruct(ctx context.Context, id uuid.UUID, userID int64, after *time.Duration) error {
+	message, err := s.getOwnedMessage(id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateDeleteAfter(id, after); err != nil {
+		return fmt.Errorf("failed to update self-destruct delay: %w", err)
+	}
+	if err := s.updateSeriesSetting(message, "delete_after", after); err != nil {
+		return err
+	}
+
+	s.logger.Info("Self-destruct updated", "message_id", id, "delete_after", after)
+	return nil
+}
+
+// recordDelivery stores where Telegram put a delivered message and, for
+// self-destructing messages, schedules its deletion.
+func (s *MessageService) recordDelivery(ctx context.Context, message *models.Message, chatID int64, telegramMessageID int) error {
+	if s.deliveryRepo == nil {
+		return nil
+	}
+
+	delivery := models.NewDelivery(message, chatID, telegramMessageID)
+	if message.DeleteAfter != nil {
+		deleteAt := delivery.DeliveredAt.Add(*message.DeleteAfter)
+		delivery.DeleteAt = &deleteAt
+	}
+
+	if err := s.deliveryRepo.Create(delivery); err != nil {
+		return fmt.Errorf("failed to create delivery: %w", err)
+	}
+
+	if delivery.DeleteAt != nil && s.scheduler != nil {
+		if err := s.scheduler.ScheduleDeletion(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to schedule deletion: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deliveredChatID returns the chat Telegram put a message in, falling back to
+// the numeric target it was addressed to.
+func deliveredChatID(message *models.Message, sent tgbotapi.Message) int64 {
+	if sent.Chat != nil {
+		return sent.Chat.ID
+	}
+	if target, err := resolveChatTarget(message); err == nil {
+		return target.chatID
+	}
+	return 0
+}
+
+// DeleteDeliveredMessage removes the delivered copy of a self-destructing
+// message from its chat.
+func (s *MessageService) DeleteDeliveredMessage(ctx context.Context, messageID uuid.UUID) error {
+	if s.deliveryRepo == nil || s.sender == nil {
+		return fmt.Errorf("deliveries are not configured")
+	}
+
+	delivery, err := s.deliveryRepo.GetByMessageID(messageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMessageNotPending
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get delivery: %w", err)
+	}
+	if delivery.RemovedAt != nil {
+		return ErrMessageNotPending
+	}
+
+	if err := s.sender.DeleteMessage(ctx, delivery.ChatID, delivery.TelegramMessageID); err != nil {
+		// Telegram refuses for good when the message is gone or too old
+		if !isPermanentDeliveryError(err) {
+			return err
+		}
+		s.logger.Warn("Delivered message could not be deleted", "error", err, "message_id", messageID)
+	}
+
+	if err := s.deliveryRepo.MarkRemoved(delivery.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark delivery as removed: %w", err)
+	}
+
+	s.logger.Info("Delivered message deleted", "message_id", messageID, "chat_id", delivery.ChatID)
+	return nil
+}
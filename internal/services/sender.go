@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+// Telegram flood limits for bots
+const (
+	globalSendRate  = 30.0      // messages per second across all chats
+	privateChatRate = 1.0       // messages per second in a private chat
+	groupChatRate   = 20.0 / 60 // messages per second in a group or channel
+
+	bucketCleanupInterval = time.Minute
+)
+
+type SendPriority int
+
+const (
+	// PriorityInteractive is used for direct replies to users and is always
+	// served before background traffic
+	PriorityInteractive SendPriority = iota
+	// PriorityBackground is used for scheduled deliveries and notices
+	PriorityBackground
+)
+
+type sendResult struct {
+	message tgbotapi.Message
+	err     error
+}
+
+type sendRequest struct {
+	ctx       context.Context
+	priority  SendPriority
+	chattable tgbotapi.Chattable
+	chatKey   string
+	group     bool
+	notBefore time.Time
+	result    chan sendResult
+}
+
+// TelegramSender delivers outgoing messages through the Telegram bot API.
+// Every request goes through one outbound queue that respects Telegram's
+// global and per-chat rate limits and retries requests answered with 429.
+type TelegramSender struct {
+	api    *tgbotapi.BotAPI
+	logger *utils.Logger
+
+	mu      sync.Mutex
+	queues  [2][]*sendRequest
+	global  *tokenBucket
+	chats   map[string]*tokenBucket
+	pending chan struct{}
+}
+
+func NewTelegramSender(api *tgbotapi.BotAPI, logger *utils.Logger) *TelegramSender {
+	return &TelegramSender{
+		api:     api,
+		logger:  logger,
+		global:  newTokenBucket(globalSendRate, globalSendRate),
+		chats:   make(map[string]*tokenBucket),
+		pending: make(chan struct{}, 1),
+	}
+}
+
+// Send queues a background send and waits until Telegram accepts it.
+func (s *TelegramSender) Send(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return s.enqueue(ctx, PriorityBackground, c)
+}
+
+// SendInteractive queues a reply to a user ahead of background traffic.
+func (s *TelegramSender) SendInteractive(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return s.enqueue(ctx, PriorityInteractive, c)
+}
+
+// DeleteMessage removes a message the bot sent earlier. Deletions do not
+// count against the send limits and bypass the queue.
+func (s *TelegramSender) DeleteMessage(ctx context.Context, chatID int64, messageID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, err := s.api.Request(tgbotapi.NewDeleteMessage(chatID, messageID)); err != nil {
+		return fmt.Errorf("telegram rejected deletion: %w", err)
+	}
+	return nil
+}
+
+func (s *TelegramSender) enqueue(ctx context.Context, priority SendPriority, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	chatKey, group, err := chatKeyOf(c)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	req := &sendRequest{
+		ctx:       ctx,
+		priority:  priority,
+		chattable: c,
+		chatKey:   chatKey,
+		group:     group,
+		result:    make(chan sendResult, 1),
+	}
+
+	s.mu.Lock()
+	s.queues[priority] = append(s.queues[priority], req)
+	s.mu.Unlock()
+	s.signal()
+
+	select {
+	case res := <-req.result:
+		return res.message, res.err
+	case <-ctx.Done():
+		return tgbotapi.Message{}, ctx.Err()
+	}
+}
+
+func (s *TelegramSender) signal() {
+	select {
+	case s.pending <- struct{}{}:
+	default:
+	}
+}
+
+// Start runs the dispatcher that drains the queue within the rate limits.
+func (s *TelegramSender) Start(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	cleanup := time.NewTicker(bucketCleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-s.pending:
+		case <-cleanup.C:
+			s.dropIdleBuckets()
+			continue
+		}
+
+		timer.Reset(s.dispatch())
+	}
+}
+
+// dispatch sends every request that fits the rate limits right now and
+// returns how long to wait before the next one may go out.
+func (s *TelegramSender) dispatch() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		now := time.Now()
+		wait := s.global.wait(now)
+		if wait > 0 {
+			return wait
+		}
+
+		req, next := s.nextReady(now)
+		if req == nil {
+			return next
+		}
+
+		s.global.take(now)
+		s.chatBucket(req).take(now)
+		go s.execute(req)
+	}
+}
+
+// nextReady removes and returns the first request whose chat may receive a
+// message now, checking interactive requests first. When nothing is ready it
+// returns how long until something might be.
+func (s *TelegramSender) nextReady(now time.Time) (*sendRequest, time.Duration) {
+	next := time.Hour
+	for priority := range s.queues {
+		queue := s.queues[priority]
+		for i := 0; i < len(queue); i++ {
+			req := queue[i]
+			if req.ctx.Err() != nil {
+				queue = append(queue[:i], queue[i+1:]...)
+				i--
+				continue
+			}
+
+			wait := s.chatBucket(req).wait(now)
+			if notBefore := req.notBefore.Sub(now); notBefore > wait {
+				wait = notBefore
+			}
+			if wait <= 0 {
+				s.queues[priority] = append(queue[:i], queue[i+1:]...)
+				return req, 0
+			}
+			if wait < next {
+				next = wait
+			}
+		}
+		s.queues[priority] = queue
+	}
+	return nil, next
+}
+
+func (s *TelegramSender) execute(req *sendRequest) {
+	sent, err := s.api.Send(req.chattable)
+	if err != nil {
+		var apiErr *tgbotapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == 429 && apiErr.RetryAfter > 0 {
+			s.requeue(req, time.Duration(apiErr.RetryAfter)*time.Second)
+			return
+		}
+		req.result <- sendResult{err: fmt.Errorf("telegram rejected message: %w", err)}
+		return
+	}
+
+	req.result <- sendResult{message: sent}
+}
+
+// requeue puts a request that hit flood control back at the front of its
+// queue and holds its chat until Telegram's retry_after has passed.
+func (s *TelegramSender) requeue(req *sendRequest, retryAfter time.Duration) {
+	s.logger.Warn("Telegram flood control hit, requeueing", "chat", req.chatKey, "retry_after", retryAfter)
+
+	s.mu.Lock()
+	req.notBefore = time.Now().Add(retryAfter)
+	s.chatBucket(req).pause(req.notBefore)
+	s.queues[req.priority] = append([]*sendRequest{req}, s.queues[req.priority]...)
+	s.mu.Unlock()
+
+	s.signal()
+}
+
+func (s *TelegramSender) chatBucket(req *sendRequest) *tokenBucket {
+	bucket, ok := s.chats[req.chatKey]
+	if !ok {
+		rate := privateChatRate
+		if req.group {
+			rate = groupChatRate
+		}
+		bucket = newTokenBucket(rate, 1)
+		s.chats[req.chatKey] = bucket
+	}
+	return bucket
+}
+
+func (s *TelegramSender) dropIdleBuckets() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A full bucket behaves exactly like a new one, so it can be dropped
+	now := time.Now()
+	for key, bucket := range s.chats {
+		if bucket.full(now) {
+			delete(s.chats, key)
+		}
+	}
+}
+
+// chatKeyOf identifies the chat a request targets and whether it is a group
+// or channel, which Telegram limits more strictly than private chats. Request
+// types it does not know are refused rather than sharing one bucket.
+func chatKeyOf(c tgbotapi.Chattable) (string, bool, error) {
+	var chatID int64
+	var channel string
+	switch v := c.(type) {
+	case tgbotapi.MessageConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.PhotoConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.DocumentConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.AudioConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.VideoConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.VoiceConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.AnimationConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.StickerConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.LocationConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.VenueConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.ContactConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.ChatActionConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.MediaGroupConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.EditMessageTextConfig:
+		return editKeyOf(v.BaseEdit)
+	case tgbotapi.EditMessageCaptionConfig:
+		return editKeyOf(v.BaseEdit)
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return editKeyOf(v.BaseEdit)
+	case tgbotapi.DeleteMessageConfig:
+		chatID, channel = v.ChatID, v.ChannelUsername
+	case tgbotapi.CallbackConfig:
+		// Callback answers go to the user who pressed the button, not to a chat
+		return "callback:" + v.CallbackQueryID, false, nil
+	default:
+		return "", false, fmt.Errorf("unsupported telegram request %T", c)
+	}
+
+	return chatKey(chatID, channel)
+}
+
+// editKeyOf is chatKeyOf for edits, which may target an inline message
+// instead of a chat.
+func editKeyOf(edit tgbotapi.BaseEdit) (string, bool, error) {
+	if edit.InlineMessageID != "" && edit.ChatID == 0 && edit.ChannelUsername == "" {
+		return "inline:" + edit.InlineMessageID, false, nil
+	}
+	return chatKey(edit.ChatID, edit.ChannelUsername)
+}
+
+func chatKey(chatID int64, channel string) (string, bool, error) {
+	if channel != "" {
+		return channel, true, nil
+	}
+	if chatID == 0 {
+		return "", false, errors.New("telegram request has no chat")
+	}
+	return strconv.FormatInt(chatID, 10), chatID < 0, nil
+}
+
+// tokenBucket is a token bucket rate limiter that refills continuously.
+type tokenBucket struct {
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// wait returns how long until a token is available.
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) take(now time.Time) {
+	b.refill(now)
+	b.tokens--
+}
+
+func (b *tokenBucket) pause(until time.Time) {
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+func (b *tokenBucket) full(now time.Time) bool {
+	if now.Before(b.pausedUntil) {
+		return false
+	}
+
+	b.refill(now)
+	return b.tokens >= b.burst
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+// SeriesScope selects which occurrences of a recurring series an edit or a
+// cancellation applies to.
+type SeriesScope string
+
+const (
+	ScopeOccurrence SeriesScope = "this"
+	ScopeFollowing  SeriesScope = "following"
+	ScopeSeries     SeriesScope = "all"
+)
+
+// ParseSeriesScope parses "this", "following" or "all".
+func ParseSeriesScope(value string) (SeriesScope, bool) {
+	switch scope := SeriesScope(value); scope {
+	case ScopeOccurrence, ScopeFollowing, ScopeSeries:
+		return scope, true
+	}
+	return "", false
+}
+
+// InSeries reports whether a message is an occurrence of a recurring series,
+// so edits and cancellations need a scope.
+func InSeries(message *models.Message) bool {
+	return message.SeriesID != nil || message.RecurrenceType != models.RecurrenceNone
+}
+
+// EditMessage replaces the text of a pending or paused message. For an
+// occurrence of a series, ScopeFollowing splits the series off at this
+// occurrence and ScopeSeries changes the template of the whole series.
+func (s *MessageService) EditMessage(ctx context.Context, id uuid.UUID, userID int64, scope SeriesScope, content string) error {
+	message, err := s.getOpenMessage(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if s.seriesRepo == nil || !InSeries(message) || scope == ScopeOccurrence {
+		message.Content = content
+		return s.UpdateMessage(ctx, message)
+	}
+
+	series, err := s.getSeries(message)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := s.encryptContent(content)
+	if err != nil {
+		return err
+	}
+
+	occurrences, err := s.repo.GetOpenBySeries(series.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get series occurrences: %w", err)
+	}
+
+	if scope == ScopeFollowing {
+		// Earlier occurrences stay with the old series
+		if err := s.seriesRepo.UpdateStatus(series.ID, models.SeriesStatusEnded); err != nil {
+			return fmt.Errorf("failed to end series: %w", err)
+		}
+		series.ID = uuid.New()
+		series.Status = models.SeriesStatusActive
+		series.Content = encrypted
+		series.CreatedAt = time.Now()
+		series.UpdatedAt = time.Now()
+		if err := s.seriesRepo.Create(series); err != nil {
+			return fmt.Errorf("failed to create series: %w", err)
+		}
+	} else {
+		series.Content = encrypted
+		series.UpdatedAt = time.Now()
+		if err := s.seriesRepo.Update(series); err != nil {
+			return fmt.Errorf("failed to update series: %w", err)
+		}
+	}
+
+	for _, occurrence := range occurrences {
+		if scope == ScopeFollowing && occurrence.ScheduledTime.Before(message.ScheduledTime) {
+			continue
+		}
+		occurrence.SeriesID = &series.ID
+		occurrence.Content = content
+		if err := s.UpdateMessage(ctx, occurrence); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("Series edited", "series_id", series.ID, "message_id", id, "scope", scope)
+	return nil
+}
+
+// CancelOccurrences cancels a pending or paused message. For an occurrence
+// of a series, ScopeOccurrence skips to the next occurrence, ScopeFollowing
+// ends the series here and ScopeSeries cancels the series altogether.
+func (s *MessageService) CancelOccurrences(ctx context.Context, id uuid.UUID, userID int64, scope SeriesScope) error {
+	message, err := s.GetMessage(ctx, id)
+	if err != nil {
+		return err
+	}
+	if message.UserID != userID {
+		return fmt.Errorf("message does not belong to user")
+	}
+
+	if s.seriesRepo == nil || !InSeries(message) {
+		if !isOpen(message) {
+			return ErrMessageNotPending
+		}
+		return s.CancelMessage(ctx, id, userID)
+	}
+
+	series, err := s.getSeries(message)
+	if err != nil {
+		return err
+	}
+
+	switch scope {
+	case ScopeSeries:
+		if err := s.seriesRepo.UpdateStatus(series.ID, models.SeriesStatusCancelled); err != nil {
+			return fmt.Errorf("failed to cancel series: %w", err)
+		}
+		occurrences, err := s.repo.GetOpenBySeries(series.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get series occurrences: %w", err)
+		}
+		for _, occurrence := range occurrences {
+			if err := s.CancelMessage(ctx, occurrence.ID, userID); err != nil {
+				return err
+			}
+		}
+	case ScopeFollowing:
+		if !isOpen(message) {
+			return ErrMessageNotPending
+		}
+		if err := s.seriesRepo.UpdateStatus(series.ID, models.SeriesStatusEnded); err != nil {
+			return fmt.Errorf("failed to end series: %w", err)
+		}
+		if err := s.CancelMessage(ctx, id, userID); err != nil {
+			return err
+		}
+	default:
+		if !isOpen(message) {
+			return ErrMessageNotPending
+		}
+		if err := s.CancelMessage(ctx, id, userID); err != nil {
+			return err
+		}
+		if err := s.handleRecurrence(ctx, message, true); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("Series occurrences cancelled", "series_id", series.ID, "message_id", id, "scope", scope)
+	return nil
+}
+
+// updateSeriesSetting applies a setting changed on an occurrence to the
+// template of its series, so that later occurrences keep it.
+func (s *MessageService) updateSeriesSetting(message *models.Message, column string, value interface{}) error {
+	if s.seriesRepo == nil || message.SeriesID == nil {
+		return nil
+	}
+	if err := s.seriesRepo.UpdateColumn(*message.SeriesID, column, value); err != nil {
+		return fmt.Errorf("failed to update series %s: %w", column, err)
+	}
+	return nil
+}
+
+// seriesTemplate returns the message the next occurrence is created from, or
+// nil when the series creates no further occurrences.
+func (s *MessageService) seriesTemplate(message *models.Message) (*models.Message, error) {
+	if s.seriesRepo == nil {
+		return message, nil
+	}
+
+	series, err := s.getSeries(message)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status != models.SeriesStatusActive {
+		return nil, nil
+	}
+	return series.Apply(message), nil
+}
+
+// getSeries returns the series of a recurring message with its content
+// decrypted. Messages scheduled before series existed get one on demand.
+func (s *MessageService) getSeries(message *models.Message) (*models.Series, error) {
+	if message.SeriesID == nil {
+		series := models.NewSeries(message)
+		encrypted, err := s.encryptContent(message.Content)
+		if err != nil {
+			return nil, err
+		}
+		series.Content = encrypted
+		if err := s.seriesRepo.Create(series); err != nil {
+			return nil, fmt.Errorf("failed to create series: %w", err)
+		}
+		if err := s.repo.UpdateSeriesID(message.ID, series.ID); err != nil {
+			return nil, fmt.Errorf("failed to link message to series: %w", err)
+		}
+		message.SeriesID = &series.ID
+		series.Content = message.Content
+		return series, nil
+	}
+
+	series, err := s.seriesRepo.GetByID(*message.SeriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get series: %w", err)
+	}
+	series.Content = s.decryptContent(series.Content)
+	return series, nil
+}
+
+func (s *MessageService) getOpenMessage(ctx context.Context, id uuid.UUID, userID int64) (*models.Message, error) {
+	message, err := s.GetMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if message.UserID != userID {
+		return nil, fmt.Errorf("message does not belong to user")
+	}
+	if !isOpen(message) {
+		return nil, ErrMessageNotPending
+	}
+	return message, nil
+}
+
+// isOpen reports whether a message is still to be delivered.
+func isOpen(message *models.Message) bool {
+	return message.Status == models.MessageStatusPending || message.Status == models.MessageStatusPaused
+}
+
+func (s *MessageService) encryptContent(content string) (string, error) {
+	if s.encryptor == nil {
+		return content, nil
+	}
+	encrypted, err := s.encryptor.Encrypt(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt message content: %w", err)
+	}
+	return encrypted, nil
+}
+
+func (s *MessageService) decryptContent(content string) string {
+	if s.encryptor == nil || content == "" {
+		return content
+	}
+	decrypted, err := s.encryptor.Decrypt(content)
+	if err != nil {
+		s.logger.Error("Failed to decrypt series content", "error", err)
+		return content
+	}
+	return decrypted
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+// Snooze options offered on delivered reminders
+const (
+	SnoozeTenMinutes = "10m"
+	SnoozeOneHour    = "1h"
+	SnoozeTomorrow   = "tomorrow"
+	SnoozeCustom     = "custom"
+)
+
+// snoozeMorningHour is when "tomorrow morning" starts in the owner's timezone
+const snoozeMorningHour = 9
+
+func snoozeKeyboard(message *models.Message, language models.UserLanguage) tgbotapi.InlineKeyboardMarkup {
+	button := func(option string) tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(getText("snooze_"+option, language),
+			"snooze_"+option+"_"+message.ID.String())
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(button(SnoozeTenMinutes), button(SnoozeOneHour)),
+		tgbotapi.NewInlineKeyboardRow(button(SnoozeTomorrow), button(SnoozeCustom)),
+	)
+}
+
+// SnoozeUntil returns when a fixed snooze option ends, counted from now in
+// the given timezone.
+func SnoozeUntil(option, timezone string, now time.Time) (time.Time, error) {
+	switch option {
+	case SnoozeTenMinutes:
+		return now.Add(10 * time.Minute), nil
+	case SnoozeOneHour:
+		return now.Add(time.Hour), nil
+	case SnoozeTomorrow:
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		local := now.In(loc)
+		return time.Date(local.Year(), local.Month(), local.Day()+1, snoozeMorningHour, 0, 0, 0, loc), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown snooze option: %s", option)
+	}
+}
+
+// SnoozeMessage schedules a one-off copy of a delivered message for the
+// given time and returns the copy.
+func (s *MessageService) SnoozeMessage(ctx context.Context, id uuid.UUID, userID int64, until time.Time) (*models.Message, error) {
+	message, err := s.GetMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if message.UserID != userID {
+		return nil, fmt.Errorf("message does not belong to user")
+	}
+	if message.Status != models.MessageStatusSent {
+		return nil, fmt.Errorf("message has not been delivered")
+	}
+	if !until.After(time.Now()) {
+		return nil, fmt.Errorf("snooze time is in the past")
+	}
+
+	snoozed := *message
+	snoozed.ID = uuid.New()
+	snoozed.ScheduledTime = until
+	snoozed.Status = models.MessageStatusPending
+	snoozed.FailureReason = nil
+	snoozed.Attempts = 0
+	snoozed.SeriesID = nil
+	snoozed.RecurrenceType = models.RecurrenceNone
+	snoozed.RecurrenceCount = 0
+	snoozed.MaxRecurrences = nil
+	snoozed.Reminders = nil
+	snoozed.DeliveryWindow = nil
+	snoozed.CreatedAt = time.Now()
+	snoozed.UpdatedAt = time.Now()
+
+	if err := s.CreateMessage(ctx, &snoozed); err != nil {
+		return nil, fmt.Errorf("failed to create snoozed message: %w", err)
+	}
+
+	s.logger.Info("Message snoozed", "original_id", id, "new_id", snoozed.ID, "scheduled_time", until)
+	return &snoozed, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import "github.com/MostafaSensei106/Riko-Chan/internal/models"
+
+// getText returns localized text for notices the services send to users on
+// their own, outside of a bot conversation
+func getText(key string, language models.UserLanguage) string {
+	texts := map[models.UserLanguage]map[string]string{
+		models.LanguageEnglish: {
+			"delivery_failed": "⚠️ Your scheduled message could not be delivered after %d attempts.\n💬 %s\n❗ %s\n🆔 %s",
+			"retry_delivery":  "🔁 Retry",
+			"message_missed":  "⏰ Your message scheduled for %s was not delivered because it was %s late.\n💬 %s\n🆔 %s",
+			"reminder":        "🔔 Your message goes out in %s (%s).\n💬 %s\n🆔 %s",
+			"send_now":        "📤 Send now",
+			"postpone":        "⏳ +1h",
+			"cancel":          "❌ Cancel",
+			"snooze_10m":      "💤 10 min",
+			"snooze_1h":       "💤 1 hour",
+			"snooze_tomorrow": "🌅 Tomorrow morning",
+			"snooze_custom":   "🕒 Custom",
+		},
+		models.LanguageArabic: {
+			"delivery_failed": "⚠️ تعذر إرسال رسالتك المجدولة بعد %d محاولات.\n💬 %s\n❗ %s\n🆔 %s",
+			"retry_delivery":  "🔁 إعادة المحاولة",
+			"message_missed":  "⏰ لم يتم إرسال رسالتك المجدولة في %s لأنها تأخرت %s.\n💬 %s\n🆔 %s",
+			"reminder":        "🔔 سيتم إرسال رسالتك خلال %s (%s).\n💬 %s\n🆔 %s",
+			"send_now":        "📤 أرسل الآن",
+			"postpone":        "⏳ +1س",
+			"cancel":          "❌ إلغاء",
+			"snooze_10m":      "💤 10 دقائق",
+			"snooze_1h":       "💤 ساعة",
+			"snooze_tomorrow": "🌅 صباح الغد",
+			"snooze_custom":   "🕒 وقت آخر",
+		},
+	}
+
+	if langTexts, exists := texts[language]; exists {
+		if text, exists := langTexts[key]; exists {
+			return text
+		}
+	}
+
+	// Fallback to English
+	if langTexts, exists := texts[models.LanguageEnglish]; exists {
+		if text, exists := langTexts[key]; exists {
+			return text
+		}
+	}
+
+	return key // Return key if no translation found
+}
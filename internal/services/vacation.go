@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SetVacation holds every message of a user that comes due between from and
+// until. Held messages are resumed when the vacation ends.
+func (s *MessageService) SetVacation(ctx context.Context, userID int64, from, until time.Time) error {
+	if s.userRepo == nil {
+		return fmt.Errorf("no user repository configured")
+	}
+	if !until.After(from) || !until.After(time.Now()) {
+		return fmt.Errorf("vacation must end after it starts and in the future")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user.VacationFrom = &from
+	user.VacationUntil = &until
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Messages held by an earlier vacation are free until the new one starts
+	if !user.OnVacation(time.Now()) {
+		s.resumeHeld(ctx, userID)
+	}
+
+	if s.scheduler != nil {
+		if err := s.scheduler.ScheduleVacation(ctx, userID, from, until); err != nil {
+			return fmt.Errorf("failed to schedule vacation: %w", err)
+		}
+	}
+
+	s.logger.Info("Vacation set", "user_id", userID, "from", from, "until", until)
+	return nil
+}
+
+// ClearVacation ends a user's vacation now and resumes the held messages.
+func (s *MessageService) ClearVacation(ctx context.Context, userID int64) error {
+	if s.userRepo == nil {
+		return fmt.Errorf("no user repository configured")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user.VacationFrom = nil
+	user.VacationUntil = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if s.scheduler != nil {
+		if err := s.scheduler.CancelVacation(ctx, userID); err != nil {
+			s.logger.Error("Failed to cancel vacation", "error", err, "user_id", userID)
+		}
+	}
+
+	s.resumeHeld(ctx, userID)
+
+	s.logger.Info("Vacation cleared", "user_id", userID)
+	return nil
+}
+
+// StartVacation pauses every pending message of a user whose vacation has
+// begun. It is called by the scheduler at the start of the vacation.
+func (s *MessageService) StartVacation(ctx context.Context, userID int64) error {
+	if s.userRepo == nil {
+		return fmt.Errorf("no user repository configured")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	// The vacation was moved or cleared since this job was queued
+	if !user.OnVacation(time.Now()) {
+		return nil
+	}
+
+	messages, err := s.repo.GetAllUserPendingMessages(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get pending messages: %w", err)
+	}
+
+	for _, message := range messages {
+		if err := s.pause(ctx, message, true); err != nil {
+			s.logger.Error("Failed to hold message for vacation", "error", err, "message_id", message.ID)
+		}
+	}
+
+	s.logger.Info("Vacation started", "user_id", userID, "held", len(messages))
+	return nil
+}
+
+// EndVacation clears a finished vacation and resumes the held messages. It
+// is called by the scheduler at the end of the vacation.
+func (s *MessageService) EndVacation(ctx context.Context, userID int64) error {
+	if s.userRepo == nil {
+		return fmt.Errorf("no user repository configured")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	// The vacation was extended or cleared since this job was queued
+	if user.VacationUntil == nil || user.VacationUntil.After(time.Now()) {
+		return nil
+	}
+
+	user.VacationFrom = nil
+	user.VacationUntil = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	s.resumeHeld(ctx, userID)
+
+	s.logger.Info("Vacation ended", "user_id", userID)
+	return nil
+}
+
+func (s *MessageService) resumeHeld(ctx context.Context, userID int64) {
+	messages, err := s.repo.GetHeldForVacation(userID)
+	if err != nil {
+		s.logger.Error("Failed to get messages held for vacation", "error", err, "user_id", userID)
+		return
+	}
+
+	for _, message := range messages {
+		if err := s.resume(ctx, message); err != nil {
+			s.logger.Error("Failed to resume message after vacation", "error", err, "message_id", message.ID)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/config"
+	"github.com/MostafaSensei106/Riko-Chan/internal/db"
+	"github.com/MostafaSensei106/Riko-Chan/internal/events"
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+	"github.com/MostafaSensei106/Riko-Chan/internal/utils"
+)
+
+const (
+	maxWebhooksPerUser   = 5
+	maxWebhookAttempts   = 8
+	webhookTimeout       = 10 * time.Second
+	webhookPollInterval  = 15 * time.Second
+	webhookBatchSize     = 50
+	maxWebhookRedirects  = 5
+	webhookSignatureName = "X-Riko-Signature"
+)
+
+// WebhookEvents are the events forwarded to user webhooks.
+var WebhookEvents = []events.Type{
+	events.MessageCreated,
+	events.MessageDelivered,
+	events.MessageFailed,
+	events.MessageCancelled,
+}
+
+// WebhookService posts message lifecycle events to the HTTP endpoints users
+// register. Every call is logged and failed calls are retried with backoff.
+type WebhookService struct {
+	repo      *db.WebhookRepository
+	client    *http.Client
+	logger    *utils.Logger
+	wake      chan struct{}
+	allowHTTP bool
+}
+
+func NewWebhookService(repo *db.WebhookRepository, logger *utils.Logger) *WebhookService {
+	s := &WebhookService{
+		repo:   repo,
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+	}
+	s.client = publicOnlyClient(s.checkRedirect)
+	return s
+}
+
+func (s *WebhookService) SetConfig(cfg config.WebhooksConfig) {
+	s.allowHTTP = cfg.AllowHTTP
+}
+
+// allowedScheme reports whether webhooks may use scheme; plain http only
+// when the config allows it.
+func (s *WebhookService) allowedScheme(scheme string) bool {
+	return scheme == "https" || (scheme == "http" && s.allowHTTP)
+}
+
+// checkRedirect keeps redirects to the same schemes a webhook may use.
+func (s *WebhookService) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxWebhookRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxWebhookRedirects)
+	}
+	if !s.allowedScheme(req.URL.Scheme) {
+		return fmt.Errorf("redirect to %s is not allowed", req.URL.Scheme)
+	}
+	return nil
+}
+
+// AddWebhook registers an endpoint for a user and returns it together with
+// the freshly generated signing secret. The endpoint must use https and
+// resolve to public addresses only.
+func (s *WebhookService) AddWebhook(ctx context.Context, userID int64, rawURL string) (*models.Webhook, error) {
+	endpoint, err := url.Parse(rawURL)
+	if err != nil || !s.allowedScheme(endpoint.Scheme) || endpoint.Hostname() == "" {
+		return nil, fmt.Errorf("invalid webhook url: %s", rawURL)
+	}
+	if err := checkPublicHost(ctx, endpoint.Hostname()); err != nil {
+		return nil, fmt.Errorf("invalid webhook url: %w", err)
+	}
+
+	existing, err := s.repo.GetByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	if len(existing) >= maxWebhooksPerUser {
+		return nil, fmt.Errorf("at most %d webhooks are allowed", maxWebhooksPerUser)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	webhook := models.NewWebhook(userID, endpoint.String(), hex.EncodeToString(secret))
+	if err := s.repo.Create(webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	s.logger.Info("Webhook added", "webhook_id", webhook.ID, "user_id", userID)
+	return webhook, nil
+}
+
+func (s *WebhookService) RemoveWebhook(ctx context.Context, id uuid.UUID, userID int64) error {
+	webhook, err := s.repo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if webhook.UserID != userID {
+		return fmt.Errorf("webhook does not belong to user")
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	s.logger.Info("Webhook removed", "webhook_id", id, "user_id", userID)
+	return nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context, userID int64) ([]*models.Webhook, error) {
+	webhooks, err := s.repo.GetByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// RecentDeliveries returns the delivery log of a user's webhooks, newest first.
+func (s *WebhookService) RecentDeliveries(ctx context.Context, userID int64, limit int) ([]*models.WebhookDelivery, error) {
+	deliveries, err := s.repo.GetRecentDeliveries(userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// HandleEvent queues an event for every webhook of its owner. It only writes
+// the delivery log, the HTTP calls happen on the delivery loop.
+func (s *WebhookService) HandleEvent(ctx context.Context, event events.Event) error {
+	webhooks, err := s.repo.GetByUser(event.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		delivery := models.NewWebhookDelivery(webhook, event.ID, string(event.Type), string(payload))
+		if err := s.repo.CreateDelivery(delivery); err != nil {
+			return fmt.Errorf("failed to queue webhook delivery: %w", err)
+		}
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start runs the delivery loop, posting queued events and retrying failed
+// calls once their backoff has passed.
+func (s *WebhookService) Start(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.deliverDue(ctx); err != nil {
+			s.logger.Error("Failed to deliver webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *WebhookService) deliverDue(ctx context.Context) error {
+	deliveries, err := s.repo.GetDueDeliveries(time.Now(), maxWebhookAttempts, webhookBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	webhooks := make(map[uuid.UUID]*models.Webhook)
+	for _, delivery := range deliveries {
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			if webhook, err = s.repo.GetByID(delivery.WebhookID); err != nil {
+				s.logger.Error("Failed to get webhook", "error", err, "webhook_id", delivery.WebhookID)
+				continue
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+		s.deliver(ctx, webhook, delivery)
+	}
+	return nil
+}
+
+func (s *WebhookService) deliver(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	attempts := delivery.Attempts + 1
+	statusCode, err := s.post(ctx, webhook, delivery)
+	if err == nil {
+		if err := s.repo.MarkDelivered(delivery.ID, attempts, statusCode, time.Now()); err != nil {
+			s.logger.Error("Failed to mark webhook delivered", "error", err, "delivery_id", delivery.ID)
+		}
+		return
+	}
+
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	next := time.Now().Add(retryDelay(attempts))
+	if err := s.repo.RecordDeliveryFailure(delivery.ID, attempts, code, next, err.Error()); err != nil {
+		s.logger.Error("Failed to record webhook failure", "error", err, "delivery_id", delivery.ID)
+	}
+
+	if attempts >= maxWebhookAttempts {
+		s.logger.Error("Webhook delivery failed permanently", "error", err, "webhook_id", webhook.ID, "delivery_id", delivery.ID)
+		return
+	}
+	s.logger.Warn("Webhook delivery failed, retrying", "error", err, "webhook_id", webhook.ID,
+		"delivery_id", delivery.ID, "attempt", attempts, "retry_at", next)
+}
+
+// post sends the payload signed with the webhook secret. Receivers verify it
+// by computing HMAC-SHA256 over the raw body.
+func (s *WebhookService) post(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Riko-Event", delivery.EventType)
+	req.Header.Set("X-Riko-Delivery", delivery.ID.String())
+	req.Header.Set(webhookSignatureName, "sha256="+signPayload(webhook.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+// MaxDeliveryWindow bounds the delivery window of a message
+const MaxDeliveryWindow = 24 * time.Hour
+
+// ErrWindowTooLong is returned for a delivery window over MaxDeliveryWindow.
+var ErrWindowTooLong = errors.New("delivery window is too long")
+
+// SetDeliveryWindow makes a message go out at a random time within window
+// after its scheduled time. A nil window delivers it on time.
+func (s *MessageService) SetDeliveryWindow(ctx context.Context, id uuid.UUID, userID int64, window *time.Duration) error {
+	if window != nil && *window > MaxDeliveryWindow {
+		return ErrWindowTooLong
+	}
+
+	message, err := s.getOwnedMessage(id, userID)
+	if err != nil {
+		return err
+	}
+	if !isOpen(message) {
+		return ErrMessageNotPending
+	}
+
+	if err := s.repo.UpdateDeliveryWindow(id, window); err != nil {
+		return fmt.Errorf("failed to update delivery window: %w", err)
+	}
+	if err := s.updateSeriesSetting(message, "delivery_window", window); err != nil {
+		return err
+	}
+	message.DeliveryWindow = window
+
+	if s.scheduler != nil && message.Status == models.MessageStatusPending {
+		if err := s.scheduler.ScheduleMessage(ctx, message); err != nil {
+			return fmt.Errorf("failed to reschedule message: %w", err)
+		}
+	}
+
+	s.logger.Info("Delivery window updated", "message_id", id, "delivery_window", window)
+	return nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/MostafaSensei106/Riko-Chan/internal/models"
+)
+
+// ErrSkipNeedsRecurrence is returned when a one-off message is given the skip
+// workday policy, which would leave it with nothing to deliver.
+var ErrSkipNeedsRecurrence = errors.New("skip applies to recurring messages only")
+
+// HolidayCalendars lists the holiday calendars users can choose from.
+func (s *MessageService) HolidayCalendars() []string {
+	if s.calendars == nil {
+		return nil
+	}
+	return s.calendars.Names()
+}
+
+// HasHolidayCalendar reports whether a holiday calendar was loaded.
+func (s *MessageService) HasHolidayCalendar(name string) bool {
+	if s.calendars == nil {
+		return false
+	}
+	_, ok := s.calendars.Get(name)
+	return ok
+}
+
+// SetWorkdayPolicy decides what happens when a message falls on a weekend day
+// or holiday of its owner. A nil policy delivers it regardless. The pending
+// occurrence moves right away when the policy calls for it.
+func (s *MessageService) SetWorkdayPolicy(ctx context.Context, id uuid.UUID, userID int64, policy *models.WorkdayPolicy) (*models.Message, error) {
+	message, err := s.getOwnedMessage(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isOpen(message) {
+		return nil, ErrMessageNotPending
+	}
+	if policy != nil && *policy == models.WorkdaySkip && message.RecurrenceType == models.RecurrenceNone {
+		return nil, ErrSkipNeedsRecurrence
+	}
+
+	if err := s.repo.UpdateWorkdayPolicy(id, policy); err != nil {
+		return nil, fmt.Errorf("failed to update workday policy: %w", err)
+	}
+	if err := s.updateSeriesSetting(message, "workday_policy", policy); err != nil {
+		return nil, err
+	}
+	message.WorkdayPolicy = policy
+
+	if err := s.alignToWorkday(ctx, message); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Workday policy updated", "message_id", id, "policy", policy)
+	return message, nil
+}
+
+// alignToWorkday moves a pending or paused message that falls on a
+// non-working day as its workday policy says.
+func (s *MessageService) alignToWorkday(ctx context.Context, message *models.Message) error {
+	at, ok := s.workdayTime(message)
+	if !ok || at.Equal(message.ScheduledTime) {
+		return nil
+	}
+
+	if err := s.repo.UpdateScheduledTime(message.ID, at); err != nil {
+		return fmt.Errorf("failed to move message to a working day: %w", err)
+	}
+	message.ScheduledTime = at
+
+	if s.scheduler != nil && message.Status == models.MessageStatusPending {
+		if err := s.scheduler.ScheduleMessage(ctx, message); err != nil {
+			return fmt.Errorf("failed to reschedule message: %w", err)
+		}
+	}
+
+	s.logger.Info("Message moved to a working day", "message_id", message.ID, "scheduled_time", at)
+	return nil
+}
+
+// workdayTime returns when a message goes out under its workday policy; a
+// skipped occurrence gives way to the next one. It reports false when no
+// occurrence is left.
+func (s *MessageService) workdayTime(message *models.Message) (time.Time, bool) {
+	rc := s.recurrenceContext(message)
+
+	var policy models.WorkdayPolicy
+	if message.WorkdayPolicy != nil {
+		policy = *message.WorkdayPolicy
+	}
+	if message.RecurrenceType == models.RecurrenceWorkdays {
+		policy = models.WorkdaySkip
+	}
+
+	if at, ok := rc.onWorkingDay(message.ScheduledTime, policy); ok {
+		return at, true
+	}
+	return nextOccurrence(message, rc, message.ScheduledTime)
+}
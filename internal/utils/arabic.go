@@ -0,0 +1,284 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// arabicReplacer folds spelling variants together: hamza forms of alef,
+// alef maqsura, taa marbuta, tatweel and short vowels.
+var arabicReplacer = strings.NewReplacer(
+	"أ", "ا", "إ", "ا", "آ", "ا", "ى", "ي", "ة", "ه", "ـ", "", "،", " ",
+	"ً", "", "ٌ", "", "ٍ", "", "َ", "", "ُ", "", "ِ", "", "ّ", "", "ْ", "",
+)
+
+func normalizeArabic(s string) string {
+	return arabicReplacer.Replace(s)
+}
+
+// arabicKeys normalizes the keys of a vocabulary map so that lookups match
+// whichever spelling the user typed.
+func arabicKeys[V any](words map[string]V) map[string]V {
+	normalized := make(map[string]V, len(words))
+	for word, value := range words {
+		normalized[normalizeArabic(word)] = value
+	}
+	return normalized
+}
+
+func arabicSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[normalizeArabic(word)] = true
+	}
+	return set
+}
+
+// arabicUnit is a unit word. Singular and dual forms carry their count;
+// plural forms take it from the number before them.
+type arabicUnit struct {
+	unit  offsetUnit
+	count int
+}
+
+var arabicDurationUnits = arabicKeys(map[string]arabicUnit{
+	"ثانية": {unitSecond, 1}, "ثانيتين": {unitSecond, 2}, "ثانيتان": {unitSecond, 2}, "ثواني": {unitSecond, 0}, "ثوان": {unitSecond, 0},
+	"دقيقة": {unitMinute, 1}, "دقيقتين": {unitMinute, 2}, "دقيقتان": {unitMinute, 2}, "دقائق": {unitMinute, 0}, "دقايق": {unitMinute, 0},
+	"ساعة": {unitHour, 1}, "ساعتين": {unitHour, 2}, "ساعتان": {unitHour, 2}, "ساعات": {unitHour, 0},
+	"يوم": {unitDay, 1}, "يومين": {unitDay, 2}, "يومان": {unitDay, 2}, "أيام": {unitDay, 0},
+	"أسبوع": {unitWeek, 1}, "أسبوعين": {unitWeek, 2}, "أسبوعان": {unitWeek, 2}, "أسابيع": {unitWeek, 0},
+	"شهر": {unitMonth, 1}, "شهرين": {unitMonth, 2}, "شهران": {unitMonth, 2}, "أشهر": {unitMonth, 0}, "شهور": {unitMonth, 0},
+	"سنة": {unitYear, 1}, "سنتين": {unitYear, 2}, "سنتان": {unitYear, 2}, "سنوات": {unitYear, 0}, "سنين": {unitYear, 0},
+	"عام": {unitYear, 1}, "عامين": {unitYear, 2}, "عامان": {unitYear, 2}, "أعوام": {unitYear, 0},
+})
+
+// arabicFractions are fractions of a unit, as in "نصف ساعة" or "ساعة ونصف"
+var arabicFractions = arabicKeys(map[string]float64{
+	"نصف": 0.5, "ربع": 0.25, "ثلث": 1.0 / 3,
+})
+
+var (
+	arabicRelativeDays = arabicKeys(map[string]int{
+		"اليوم": 0, "الليلة": 0, "غداً": 1, "غدا": 1, "غد": 1, "بكرة": 1,
+	})
+	arabicDayAfter = arabicSet("غد", "غدا", "بكرة")
+	arabicTonight  = normalizeArabic("الليلة")
+
+	arabicWeekdays = arabicKeys(map[string]time.Weekday{
+		"الأحد": time.Sunday, "الاثنين": time.Monday, "الثلاثاء": time.Tuesday, "الأربعاء": time.Wednesday,
+		"الخميس": time.Thursday, "الجمعة": time.Friday, "السبت": time.Saturday,
+	})
+	arabicThis = arabicSet("هذا", "هذه")
+	arabicNext = arabicSet("القادم", "القادمة", "المقبل", "المقبلة", "الجاي", "الجاية")
+
+	arabicMonths = arabicKeys(map[string]time.Month{
+		"يناير": time.January, "فبراير": time.February, "مارس": time.March, "أبريل": time.April,
+		"مايو": time.May, "يونيو": time.June, "يوليو": time.July, "أغسطس": time.August,
+		"سبتمبر": time.September, "أكتوبر": time.October, "نوفمبر": time.November, "ديسمبر": time.December,
+	})
+
+	arabicAM = arabicSet("صباحاً", "صباحا", "صباح", "الصبح", "ص", "فجراً", "فجرا")
+	arabicPM = arabicSet("مساءً", "مساء", "مساءا", "م", "ظهراً", "ظهرا", "عصراً", "عصرا", "ليلاً", "ليلا", "بالليل")
+
+	arabicNoon     = arabicSet("الظهر", "ظهراً", "ظهرا")
+	arabicMidnight = [2]string{normalizeArabic("منتصف"), normalizeArabic("الليل")}
+
+	// arabicFillers carry no meaning in a time phrase
+	arabicFillers = arabicSet("في", "على", "الساعة", "يوم")
+	arabicAfter   = arabicSet("بعد", "خلال")
+)
+
+var arabicClockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?$`)
+
+var arabic = grammar{day: parseArabicDay, clock: parseArabicClock}
+
+// parseArabic parses phrases such as "بعد ساعتين", "غداً الساعة ٩",
+// "الجمعة القادمة 14:00" or "3 مارس الساعة 9 مساءً".
+func parseArabic(input string, now time.Time) (time.Time, error) {
+	words := strings.Fields(normalizeArabic(input))
+
+	// "بعد غد" is a day, not a duration
+	if len(words) > 1 && arabicAfter[words[0]] && !arabicDayAfter[words[1]] {
+		offset, err := parseArabicOffset(strings.Join(words[1:], " "))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return offset.From(now), nil
+	}
+
+	filtered := words[:0]
+	for _, word := range words {
+		if !arabicFillers[word] {
+			filtered = append(filtered, word)
+		}
+	}
+	return arabic.parse(filtered, now)
+}
+
+// parseArabicOffset parses "ساعة", "ساعتين", "3 ساعات", "ساعة ونصف",
+// "نصف ساعة" or "يوم و3 ساعات".
+func parseArabicOffset(input string) (Offset, error) {
+	var offset Offset
+	var last *arabicUnit
+	count, fraction, terms := -1.0, 0.0, 0
+
+	for _, word := range strings.Fields(normalizeArabic(input)) {
+		// "و" joins terms, on its own or attached to the next word
+		if word == "و" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(word, "و"); ok && isArabicOffsetWord(rest) {
+			word = rest
+		}
+
+		if f, ok := arabicFractions[word]; ok {
+			if count < 0 && last != nil {
+				offset.add(f, last.unit)
+			} else {
+				fraction = f
+			}
+			continue
+		}
+		if n, err := strconv.ParseFloat(word, 64); err == nil && n >= 0 {
+			count = n
+			continue
+		}
+
+		unit, ok := arabicDurationUnits[word]
+		if !ok {
+			return Offset{}, fmt.Errorf("unsupported time unit: %s", word)
+		}
+		value := count
+		switch {
+		case value >= 0:
+		case fraction > 0:
+			value = fraction
+		case unit.count > 0:
+			value = float64(unit.count)
+		default:
+			return Offset{}, fmt.Errorf("missing number before %s", word)
+		}
+		offset.add(value, unit.unit)
+		last, count, fraction = &unit, -1, 0
+		terms++
+	}
+
+	if terms == 0 || count >= 0 || fraction > 0 {
+		return Offset{}, fmt.Errorf("invalid duration: %s", input)
+	}
+	return offset, nil
+}
+
+func isArabicOffsetWord(word string) bool {
+	if _, ok := arabicDurationUnits[word]; ok {
+		return true
+	}
+	if _, ok := arabicFractions[word]; ok {
+		return true
+	}
+	_, err := strconv.ParseFloat(word, 64)
+	return err == nil
+}
+
+func parseArabicDay(p *phrase, words []string) int {
+	if len(words) > 1 && arabicAfter[words[0]] && arabicDayAfter[words[1]] {
+		p.kind, p.offset = dayOffset, 2
+		return 2
+	}
+	if offset, ok := arabicRelativeDays[words[0]]; ok {
+		p.kind, p.offset, p.tonight = dayOffset, offset, words[0] == arabicTonight
+		return 1
+	}
+
+	n := 0
+	if arabicThis[words[0]] && len(words) > 1 {
+		n = 1
+	}
+	if weekday, ok := arabicWeekdays[words[n]]; ok {
+		p.kind, p.weekday = dayWeekday, weekday
+		n++
+		if len(words) > n && arabicNext[words[n]] {
+			p.next = true
+			n++
+		}
+		return n
+	}
+
+	// "3 مارس" or "مارس 3", optionally followed by a year
+	if len(words) < 2 {
+		return 0
+	}
+	month, ok := arabicMonths[words[1]]
+	dayWord := words[0]
+	if !ok {
+		if month, ok = arabicMonths[words[0]]; !ok {
+			return 0
+		}
+		dayWord = words[1]
+	}
+	day, err := strconv.Atoi(dayWord)
+	if err != nil {
+		return 0
+	}
+	p.kind, p.month, p.day = dayDate, month, day
+	return 2 + p.parseYear(words[2:])
+}
+
+func parseArabicClock(p *phrase, words []string) int {
+	if arabicNoon[words[0]] {
+		p.setClock(12, 0, "")
+		return 1
+	}
+	if len(words) > 1 && words[0] == arabicMidnight[0] && words[1] == arabicMidnight[1] {
+		p.setClock(0, 0, "")
+		return 2
+	}
+
+	match := arabicClockPattern.FindStringSubmatch(words[0])
+	if match == nil {
+		return 0
+	}
+	hour, _ := strconv.Atoi(match[1])
+	minute := 0
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+	n := 1
+
+	// "9 ونصف", "9 وربع" and "9 إلا ربع"
+	if match[2] == "" && len(words) > n {
+		switch fraction := strings.Join(words[n:min(n+2, len(words))], " "); {
+		case strings.HasPrefix(fraction, "ونصف"):
+			minute, n = 30, n+1
+		case strings.HasPrefix(fraction, "و نصف"):
+			minute, n = 30, n+2
+		case strings.HasPrefix(fraction, "وربع"):
+			minute, n = 15, n+1
+		case strings.HasPrefix(fraction, "و ربع"):
+			minute, n = 15, n+2
+		case strings.HasPrefix(fraction, "الا ربع"):
+			hour, minute, n = hour-1, 45, n+2
+		}
+	}
+
+	meridiem := ""
+	if len(words) > n {
+		if arabicAM[words[n]] {
+			meridiem, n = "am", n+1
+		} else if arabicPM[words[n]] {
+			meridiem, n = "pm", n+1
+		}
+	}
+
+	// "إلا ربع" before one o'clock is a quarter to twelve
+	if hour == 0 && meridiem != "" {
+		hour = 12
+	}
+	if !p.setClock(hour, minute, meridiem) {
+		return 0
+	}
+	return n
+}
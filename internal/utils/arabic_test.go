@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseArabic(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	// Wednesday
+	now := time.Date(2024, time.January, 31, 10, 0, 0, 0, ny)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2024, month, day, hour, min, 0, 0, ny)
+	}
+
+	runParseCases(t, "ar", []parseCase{
+		{name: "after two hours", now: now, input: "بعد ساعتين", want: at(time.January, 31, 12, 0)},
+		{name: "after two and a half hours", now: now, input: "بعد ساعتين ونصف", want: at(time.January, 31, 12, 30)},
+		{name: "after an hour and a half", now: now, input: "بعد ساعة ونصف", want: at(time.January, 31, 11, 30)},
+		{name: "after half an hour", now: now, input: "خلال نصف ساعة", want: at(time.January, 31, 10, 30)},
+		{name: "after a day and 3 hours", now: now, input: "بعد يوم و3 ساعات", want: at(time.February, 1, 13, 0)},
+		{name: "after a month", now: now, input: "بعد شهر", want: at(time.February, 29, 10, 0)},
+		{name: "arabic digits", now: now, input: "بعد ٣ ساعات", want: at(time.January, 31, 13, 0)},
+		{name: "tomorrow", now: now, input: "غداً الساعة ٩", want: at(time.February, 1, 9, 0)},
+		{name: "tomorrow evening", now: now, input: "غدا الساعة 9 مساءً", want: at(time.February, 1, 21, 0)},
+		{name: "tomorrow half past", now: now, input: "بكرة 9 ونصف", want: at(time.February, 1, 9, 30)},
+		{name: "quarter to", now: now, input: "غدا 9 إلا ربع", want: at(time.February, 1, 8, 45)},
+		{name: "day after tomorrow", now: now, input: "بعد غد", want: at(time.February, 2, 9, 0)},
+		{name: "today passed", now: now, input: "اليوم 8:00", wantErr: ErrTimeInPast},
+		{name: "today later", now: now, input: "اليوم الساعة 11", want: at(time.January, 31, 11, 0)},
+		{name: "tonight", now: now, input: "الليلة", want: at(time.January, 31, 20, 0)},
+		{name: "next weekday", now: now, input: "الجمعة القادمة 14:00", want: at(time.February, 2, 14, 0)},
+		{name: "next same weekday", now: now, input: "الأربعاء القادم 11:00", want: at(time.February, 7, 11, 0)},
+		{name: "date", now: now, input: "3 مارس الساعة 9 مساءً", want: at(time.March, 3, 21, 0)},
+		{name: "noon", now: now, input: "غدا الظهر", want: at(time.February, 1, 12, 0)},
+		{name: "february 30", now: now, input: "30 فبراير"},
+		{name: "unknown unit", now: now, input: "بعد 3 قرون"},
+	})
+}
+
+func TestParseArabicOffset(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    Offset
+		wantErr bool
+	}{
+		{input: "ساعة", want: Offset{Duration: time.Hour}},
+		{input: "ساعتين", want: Offset{Duration: 2 * time.Hour}},
+		{input: "ساعتين ونصف", want: Offset{Duration: 150 * time.Minute}},
+		{input: "ساعتين و نصف", want: Offset{Duration: 150 * time.Minute}},
+		{input: "نصف ساعة", want: Offset{Duration: 30 * time.Minute}},
+		{input: "ربع ساعة", want: Offset{Duration: 15 * time.Minute}},
+		{input: "3 ساعات", want: Offset{Duration: 3 * time.Hour}},
+		{input: "يوم و3 ساعات", want: Offset{Days: 1, Duration: 3 * time.Hour}},
+		{input: "أسبوعين", want: Offset{Days: 14}},
+		{input: "سنة", want: Offset{Months: 12}},
+		{input: "", wantErr: true},
+		{input: "3", wantErr: true},
+		{input: "ساعات", wantErr: true},
+		{input: "3 قرون", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseArabicOffset(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("parseArabicOffset(%q) = %+v, want an error", tc.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArabicOffset(%q) error = %v", tc.input, err)
+			}
+			if got != tc.want {
+				t.Fatalf("parseArabicOffset(%q) = %+v, want %+v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	cases := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{expr: "30 8 * * 1-5"},
+		{expr: "*/15 * * * *"},
+		{expr: "0 0 9 * * mon,wed"},
+		{expr: "@daily"},
+		{expr: "@WEEKLY"},
+		{expr: "0 0 29 2 *"},
+		{expr: "0 0 30 2 *", wantErr: true},
+		{expr: "0 0 31 4,6,9,11 *", wantErr: true},
+		{expr: "* * * * * *", wantErr: true},
+		{expr: "*/10 * * * * *", wantErr: true},
+		{expr: "0,30 * * * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "0 24 * * *", wantErr: true},
+		{expr: "0 0 * 13 *", wantErr: true},
+		{expr: "0 0 * * 8", wantErr: true},
+		{expr: "5-1 * * * *", wantErr: true},
+		{expr: "* * *", wantErr: true},
+		{expr: "@often", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := ParseCron(tc.expr)
+			if tc.wantErr && err == nil {
+				t.Fatalf("ParseCron(%q) succeeded, want an error", tc.expr)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("ParseCron(%q) error = %v", tc.expr, err)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	date := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, ny)
+	}
+	cases := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{name: "weekdays skip the weekend", expr: "30 8 * * 1-5",
+			after: date(2024, time.February, 2, 9, 0), want: date(2024, time.February, 5, 8, 30)},
+		{name: "first of the month", expr: "@monthly",
+			after: date(2024, time.January, 31, 10, 0), want: date(2024, time.February, 1, 0, 0)},
+		{name: "leap day", expr: "0 0 29 2 *",
+			after: date(2024, time.March, 1, 0, 0), want: date(2028, time.February, 29, 0, 0)},
+		{name: "day of month or weekday", expr: "0 9 13 * 5",
+			after: date(2024, time.September, 1, 0, 0), want: date(2024, time.September, 6, 9, 0)},
+		{name: "sunday as 7", expr: "0 12 * * 7",
+			after: date(2024, time.January, 29, 0, 0), want: date(2024, time.February, 4, 12, 0)},
+		{name: "steps", expr: "*/20 * * * *",
+			after: date(2024, time.January, 1, 9, 41), want: date(2024, time.January, 1, 10, 0)},
+		{name: "leading seconds", expr: "30 0 9 * * *",
+			after: date(2024, time.January, 1, 9, 0), want: time.Date(2024, time.January, 1, 9, 0, 30, 0, ny)},
+		{name: "gap fires after it", expr: "30 2 * * *",
+			after: date(2024, time.March, 10, 0, 0), want: time.Date(2024, time.March, 10, 7, 30, 0, 0, time.UTC)},
+		{name: "repeated fires first", expr: "30 1 * * *",
+			after: date(2024, time.November, 3, 0, 0), want: time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC)},
+		{name: "repeated fires once", expr: "30 1 * * *",
+			after: time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC).In(ny), want: date(2024, time.November, 4, 1, 30)},
+		{name: "hourly through the repeat", expr: "0 * * * *",
+			after: time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC).In(ny), want: time.Date(2024, time.November, 3, 7, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := ParseCron(tc.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) error = %v", tc.expr, err)
+			}
+			if got := schedule.Next(tc.after); !got.Equal(tc.want) {
+				t.Fatalf("Next(%v) = %v, want %v", tc.after, got, tc.want)
+			}
+		})
+	}
+}
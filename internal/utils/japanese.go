@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var japaneseUnits = map[string]offsetUnit{
+	"秒":  unitSecond,
+	"分":  unitMinute,
+	"時間": unitHour,
+	"日":  unitDay,
+	"週":  unitWeek,
+	"週間": unitWeek,
+	"ヶ月": unitMonth,
+	"か月": unitMonth,
+	"カ月": unitMonth,
+	"ヵ月": unitMonth,
+	"ケ月": unitMonth,
+	"年":  unitYear,
+}
+
+var japaneseWeekdays = map[string]time.Weekday{
+	"日": time.Sunday, "月": time.Monday, "火": time.Tuesday, "水": time.Wednesday,
+	"木": time.Thursday, "金": time.Friday, "土": time.Saturday,
+}
+
+var (
+	japaneseTermPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)(秒|分|時間|日|週間|週|ヶ月|か月|カ月|ヵ月|ケ月|年)(半)?と?`)
+	japaneseRelativeDay = regexp.MustCompile(`^(?:(今日|きょう)|(今夜|今晩|こんや|こんばん)|(明後日|あさって)|(明日|あした|あす))`)
+	japaneseWeekday     = regexp.MustCompile(`^(?:(来週|今週)の?)?(日|月|火|水|木|金|土)曜日?`)
+	japaneseDate        = regexp.MustCompile(`^(?:(\d{4})年)?(\d{1,2})月(\d{1,2})日`)
+	japaneseClock       = regexp.MustCompile(`^(午前|午後|朝|夜|夕方)?(\d{1,2})(?:時(?:(\d{1,2})分|(半))?|:(\d{2}))`)
+	japaneseNamedClock  = regexp.MustCompile(`^(?:(正午)|(真夜中))`)
+	// japaneseParticles join the parts of a phrase, as in 明日の9時に
+	japaneseParticles = regexp.MustCompile(`^(?:の|に|は|、|,)`)
+)
+
+// parseJapanese parses phrases such as "3時間後", "明日の9時", "来週の金曜日
+// 午後3時半" or "3月3日の正午".
+func parseJapanese(input string, now time.Time) (time.Time, error) {
+	s := strings.Join(strings.Fields(input), "")
+
+	if before, ok := strings.CutSuffix(s, "後"); ok {
+		offset, err := parseJapaneseOffset(before)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return offset.From(now), nil
+	}
+
+	var p phrase
+	for s != "" {
+		if match := japaneseParticles.FindString(s); match != "" {
+			s = s[len(match):]
+			continue
+		}
+
+		n := 0
+		if p.kind == dayNone {
+			n = parseJapaneseDay(&p, s)
+		}
+		if n == 0 && !p.hasClock {
+			n = parseJapaneseClock(&p, s)
+		}
+		if n == 0 {
+			return time.Time{}, fmt.Errorf("unable to parse time: %s", input)
+		}
+		s = s[n:]
+	}
+	if p.kind == dayNone && !p.hasClock {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+	return p.resolve(now)
+}
+
+// parseJapaneseOffset parses "3時間", "1時間半", "1時間30分", "1.5時間" or
+// "1年2ヶ月".
+func parseJapaneseOffset(input string) (Offset, error) {
+	var offset Offset
+	s := strings.Join(strings.Fields(input), "")
+	if s == "" {
+		return Offset{}, fmt.Errorf("invalid duration: %s", input)
+	}
+
+	for s != "" {
+		match := japaneseTermPattern.FindStringSubmatch(s)
+		if match == nil {
+			return Offset{}, fmt.Errorf("invalid duration: %s", input)
+		}
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return Offset{}, fmt.Errorf("invalid number: %s", match[1])
+		}
+		if match[3] != "" {
+			value += 0.5
+		}
+		offset.add(value, japaneseUnits[match[2]])
+		s = s[len(match[0]):]
+	}
+	return offset, nil
+}
+
+func parseJapaneseDay(p *phrase, s string) int {
+	if match := japaneseRelativeDay.FindStringSubmatch(s); match != nil {
+		switch {
+		case match[1] != "":
+			p.kind, p.offset = dayOffset, 0
+		case match[2] != "":
+			p.kind, p.offset, p.tonight = dayOffset, 0, true
+		case match[3] != "":
+			p.kind, p.offset = dayOffset, 2
+		default:
+			p.kind, p.offset = dayOffset, 1
+		}
+		return len(match[0])
+	}
+
+	if match := japaneseWeekday.FindStringSubmatch(s); match != nil {
+		p.kind, p.weekday = dayWeekday, japaneseWeekdays[match[2]]
+		p.nextWeek = match[1] == "来週"
+		return len(match[0])
+	}
+
+	if match := japaneseDate.FindStringSubmatch(s); match != nil {
+		month, _ := strconv.Atoi(match[2])
+		if month < 1 || month > 12 {
+			return 0
+		}
+		p.kind, p.month = dayDate, time.Month(month)
+		p.day, _ = strconv.Atoi(match[3])
+		if match[1] != "" {
+			p.year, _ = strconv.Atoi(match[1])
+		}
+		return len(match[0])
+	}
+	return 0
+}
+
+func parseJapaneseClock(p *phrase, s string) int {
+	if match := japaneseNamedClock.FindStringSubmatch(s); match != nil {
+		if match[1] != "" {
+			p.setClock(12, 0, "")
+		} else {
+			p.setClock(0, 0, "")
+		}
+		return len(match[0])
+	}
+
+	match := japaneseClock.FindStringSubmatch(s)
+	if match == nil {
+		return 0
+	}
+	hour, _ := strconv.Atoi(match[2])
+	minute := 0
+	switch {
+	case match[3] != "":
+		minute, _ = strconv.Atoi(match[3])
+	case match[4] != "":
+		minute = 30
+	case match[5] != "":
+		minute, _ = strconv.Atoi(match[5])
+	}
+
+	meridiem := ""
+	switch match[1] {
+	case "午前", "朝":
+		meridiem = "am"
+	case "午後", "夜", "夕方":
+		meridiem = "pm"
+	}
+	// 午前0時 is midnight and 午後0時 is noon
+	if hour == 0 && meridiem == "am" {
+		meridiem = ""
+	} else if hour == 0 && meridiem == "pm" {
+		hour, meridiem = 12, ""
+	}
+
+	if !p.setClock(hour, minute, meridiem) {
+		return 0
+	}
+	return len(match[0])
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseJapanese(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	// Wednesday
+	now := time.Date(2024, time.January, 31, 10, 0, 0, 0, ny)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2024, month, day, hour, min, 0, 0, ny)
+	}
+
+	runParseCases(t, "ja", []parseCase{
+		{name: "hours later", now: now, input: "3時間後", want: at(time.January, 31, 13, 0)},
+		{name: "hour and a half later", now: now, input: "1時間半後", want: at(time.January, 31, 11, 30)},
+		{name: "full-width digits", now: now, input: "１時間３０分後", want: at(time.January, 31, 11, 30)},
+		{name: "month later", now: now, input: "1ヶ月後", want: at(time.February, 29, 10, 0)},
+		{name: "tomorrow", now: now, input: "明日の9時", want: at(time.February, 1, 9, 0)},
+		{name: "tomorrow with particle", now: now, input: "明日の午後3時半に", want: at(time.February, 1, 15, 30)},
+		{name: "day after tomorrow", now: now, input: "明後日", want: at(time.February, 2, 9, 0)},
+		{name: "today passed", now: now, input: "今日の8時", wantErr: ErrTimeInPast},
+		{name: "today later", now: now, input: "今日の11:00", want: at(time.January, 31, 11, 0)},
+		{name: "tonight", now: now, input: "今夜9時", want: at(time.January, 31, 21, 0)},
+		{name: "weekday", now: now, input: "金曜日の14時", want: at(time.February, 2, 14, 0)},
+		{name: "next week", now: now, input: "来週の金曜日 午後3時半", want: at(time.February, 9, 15, 30)},
+		{name: "next week monday", now: now, input: "来週の月曜日", want: at(time.February, 5, 9, 0)},
+		{name: "date at noon", now: now, input: "3月3日の正午", want: at(time.March, 3, 12, 0)},
+		{name: "date with year", now: now, input: "2025年1月15日 朝8時", want: time.Date(2025, time.January, 15, 8, 0, 0, 0, ny)},
+		{name: "pm zero is noon", now: now, input: "明日の午後0時", want: at(time.February, 1, 12, 0)},
+		{name: "february 30", now: now, input: "2月30日"},
+		{name: "invalid month", now: now, input: "13月1日"},
+	})
+}
+
+func TestParseJapaneseOffset(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    Offset
+		wantErr bool
+	}{
+		{input: "3時間", want: Offset{Duration: 3 * time.Hour}},
+		{input: "1時間半", want: Offset{Duration: 90 * time.Minute}},
+		{input: "1時間30分", want: Offset{Duration: 90 * time.Minute}},
+		{input: "1.5時間", want: Offset{Duration: 90 * time.Minute}},
+		{input: "2週間", want: Offset{Days: 14}},
+		{input: "1年2ヶ月", want: Offset{Months: 14}},
+		{input: "3日と4時間", want: Offset{Days: 3, Duration: 4 * time.Hour}},
+		{input: "", wantErr: true},
+		{input: "時間", wantErr: true},
+		{input: "3世紀", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseJapaneseOffset(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("parseJapaneseOffset(%q) = %+v, want an error", tc.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseJapaneseOffset(%q) error = %v", tc.input, err)
+			}
+			if got != tc.want {
+				t.Fatalf("parseJapaneseOffset(%q) = %+v, want %+v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// timeLocale parses times and durations written in one language.
+type timeLocale struct {
+	language    string
+	parseTime   func(input string, now time.Time) (time.Time, error)
+	parseOffset func(input string) (Offset, error)
+}
+
+// timeLocales are tried in this order, after the parser's own language
+var timeLocales = []timeLocale{
+	{language: "en", parseTime: parseEnglish, parseOffset: parseEnglishOffset},
+	{language: "ar", parseTime: parseArabic, parseOffset: parseArabicOffset},
+	{language: "ja", parseTime: parseJapanese, parseOffset: parseJapaneseOffset},
+}
+
+// orderedLocales returns the time locales with language first.
+func orderedLocales(language string) []timeLocale {
+	ordered := make([]timeLocale, 0, len(timeLocales))
+	for _, locale := range timeLocales {
+		if locale.language == language {
+			ordered = append(ordered, locale)
+		}
+	}
+	for _, locale := range timeLocales {
+		if locale.language != language {
+			ordered = append(ordered, locale)
+		}
+	}
+	return ordered
+}
+
+// digitReplacer turns Arabic-Indic, Persian and full-width digits into
+// ASCII ones, along with the full-width colon.
+var digitReplacer = func() *strings.Replacer {
+	var pairs []string
+	for _, zero := range []rune{'٠', '۰', '０'} {
+		for i := rune(0); i < 10; i++ {
+			pairs = append(pairs, string(zero+i), string('0'+i))
+		}
+	}
+	pairs = append(pairs, "：", ":", "٫", ".")
+	return strings.NewReplacer(pairs...)
+}()
+
+func normalizeDigits(s string) string {
+	return digitReplacer.Replace(s)
+}
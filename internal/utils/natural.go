@@ -0,0 +1,289 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultHour is the time of day for a day given without one
+	defaultHour = 9
+	// tonightHour is the time of day for "tonight" given without one
+	tonightHour = 20
+)
+
+var relativeDays = map[string]int{
+	"today":    0,
+	"tonight":  0,
+	"tomorrow": 1,
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var monthNames = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// fillerWords carry no meaning in a time phrase
+var fillerWords = map[string]bool{"at": true, "on": true, "the": true, "of": true}
+
+var (
+	clockPattern   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	ordinalPattern = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	yearPattern    = regexp.MustCompile(`^\d{4}$`)
+)
+
+type dayKind int
+
+const (
+	dayNone dayKind = iota
+	dayOffset
+	dayWeekday
+	dayDate
+)
+
+// phrase holds the parts of a natural-language time: at most one day and
+// at most one time of day, in either order.
+type phrase struct {
+	kind    dayKind
+	offset  int
+	tonight bool
+	weekday time.Weekday
+	next    bool
+	// nextWeek picks the weekday in the week after this one, weeks starting
+	// on Monday
+	nextWeek bool
+	month    time.Month
+	day      int
+	year     int
+
+	hasClock bool
+	hour     int
+	minute   int
+	meridiem string
+}
+
+// grammar reads the day and the time of day of a phrase in one language.
+// Each function fills in p from the start of words and returns how many
+// words it took, or 0 if they do not start with what it reads.
+type grammar struct {
+	day   func(p *phrase, words []string) int
+	clock func(p *phrase, words []string) int
+}
+
+// parse fills a phrase from words, which hold at most one day and one time
+// of day in either order, and resolves it against now.
+func (g grammar) parse(words []string, now time.Time) (time.Time, error) {
+	if len(words) == 0 {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+
+	var p phrase
+	for i := 0; i < len(words); {
+		n := 0
+		if p.kind == dayNone {
+			n = g.day(&p, words[i:])
+		}
+		if n == 0 && !p.hasClock {
+			n = g.clock(&p, words[i:])
+		}
+		if n == 0 {
+			return time.Time{}, fmt.Errorf("unable to parse time: %s", strings.Join(words, " "))
+		}
+		i += n
+	}
+	return p.resolve(now)
+}
+
+var english = grammar{day: parseEnglishDay, clock: parseEnglishClock}
+
+// parseEnglish parses phrases such as "tomorrow 9:00", "next friday at 2pm",
+// "in 3 hours", "after 2 days", "tonight" or "march 3rd at noon".
+func parseEnglish(input string, now time.Time) (time.Time, error) {
+	var words []string
+	for _, word := range strings.Fields(strings.ReplaceAll(input, ",", " ")) {
+		if !fillerWords[word] {
+			words = append(words, word)
+		}
+	}
+
+	if len(words) > 0 && (words[0] == "in" || words[0] == "after") {
+		offset, err := parseEnglishOffset(strings.Join(words[1:], " "))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return offset.From(now), nil
+	}
+
+	return english.parse(words, now)
+}
+
+func parseEnglishDay(p *phrase, words []string) int {
+	if offset, ok := relativeDays[words[0]]; ok {
+		p.kind, p.offset, p.tonight = dayOffset, offset, words[0] == "tonight"
+		return 1
+	}
+
+	if (words[0] == "this" || words[0] == "next") && len(words) > 1 {
+		if weekday, ok := weekdayNames[words[1]]; ok {
+			p.kind, p.weekday, p.next = dayWeekday, weekday, words[0] == "next"
+			return 2
+		}
+	}
+	if weekday, ok := weekdayNames[words[0]]; ok {
+		p.kind, p.weekday = dayWeekday, weekday
+		return 1
+	}
+
+	// "march 3rd" or "3 march", optionally followed by a year
+	if len(words) < 2 {
+		return 0
+	}
+	month, ok := monthNames[words[0]]
+	dayWord := words[1]
+	if !ok {
+		if month, ok = monthNames[words[1]]; !ok {
+			return 0
+		}
+		dayWord = words[0]
+	}
+	match := ordinalPattern.FindStringSubmatch(dayWord)
+	if match == nil {
+		return 0
+	}
+	p.kind, p.month = dayDate, month
+	p.day, _ = strconv.Atoi(match[1])
+	return 2 + p.parseYear(words[2:])
+}
+
+// parseYear reads an optional year after a date.
+func (p *phrase) parseYear(words []string) int {
+	if len(words) > 0 && yearPattern.MatchString(words[0]) {
+		p.year, _ = strconv.Atoi(words[0])
+		return 1
+	}
+	return 0
+}
+
+func parseEnglishClock(p *phrase, words []string) int {
+	switch words[0] {
+	case "noon":
+		p.hasClock, p.hour, p.minute = true, 12, 0
+		return 1
+	case "midnight":
+		p.hasClock, p.hour, p.minute = true, 0, 0
+		return 1
+	}
+
+	match := clockPattern.FindStringSubmatch(words[0])
+	if match == nil {
+		return 0
+	}
+	hour, _ := strconv.Atoi(match[1])
+	minute := 0
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+	n, meridiem := 1, match[3]
+	if meridiem == "" && len(words) > 1 && (words[1] == "am" || words[1] == "pm") {
+		n, meridiem = 2, words[1]
+	}
+
+	if !p.setClock(hour, minute, meridiem) {
+		return 0
+	}
+	return n
+}
+
+// setClock sets the time of day, with meridiem "am", "pm" or "" for the
+// 24-hour clock, and reports whether it is a valid one.
+func (p *phrase) setClock(hour, minute int, meridiem string) bool {
+	if minute < 0 || minute > 59 || hour < 0 || hour > 23 || (meridiem != "" && (hour < 1 || hour > 12)) {
+		return false
+	}
+	p.hasClock, p.hour, p.minute, p.meridiem = true, hour, minute, meridiem
+	return true
+}
+
+// resolve turns the phrase into a time on the wall clock of now. A bare time
+// of day is the next time the clock shows it; a time "today" or "tonight"
+// must not have passed; a weekday is the next one on or after today,
+// skipping today with "next" or when the time has passed; a date without a
+// year is the next time it comes round.
+func (p *phrase) resolve(now time.Time) (time.Time, error) {
+	hour, minute := p.hour, p.minute
+	switch {
+	case !p.hasClock && p.tonight:
+		hour = tonightHour
+	case !p.hasClock:
+		hour = defaultHour
+	case p.meridiem == "pm" && hour < 12, p.meridiem == "" && p.tonight && hour < 12:
+		hour += 12
+	case p.meridiem == "am" && hour == 12:
+		hour = 0
+	}
+
+	loc := now.Location()
+	year, month, day := now.Date()
+	at := func(year int, month time.Month, day int) time.Time {
+		return LocalTime(year, month, day, hour, minute, 0, loc)
+	}
+
+	switch p.kind {
+	case dayOffset:
+		t := at(year, month, day+p.offset)
+		if !t.After(now) {
+			return time.Time{}, ErrTimeInPast
+		}
+		return t, nil
+	case dayWeekday:
+		if p.nextWeek {
+			monday := day + 7 - (int(now.Weekday())+6)%7
+			return at(year, month, monday+(int(p.weekday)+6)%7), nil
+		}
+		days := (int(p.weekday) - int(now.Weekday()) + 7) % 7
+		if days == 0 && (p.next || !at(year, month, day).After(now)) {
+			days = 7
+		}
+		return at(year, month, day+days), nil
+	case dayDate:
+		if p.year == 0 {
+			p.year = year
+			if !at(p.year, p.month, p.day).After(now) {
+				p.year++
+			}
+		}
+		if p.day < 1 || p.day > daysIn(p.year, p.month) {
+			return time.Time{}, fmt.Errorf("invalid date: %s %d", p.month, p.day)
+		}
+		return at(p.year, p.month, p.day), nil
+	}
+
+	t := at(year, month, day)
+	if !t.After(now) {
+		t = at(year, month, day+1)
+	}
+	return t, nil
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", name, err)
+	}
+	return loc
+}
+
+// parserAt returns a parser for language whose clock is fixed at now.
+func parserAt(t *testing.T, language string, now time.Time) *TimeParser {
+	t.Helper()
+	tp, err := NewTimeParser(now.Location().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp.SetLanguage(language)
+	tp.SetClock(func() time.Time { return now })
+	return tp
+}
+
+type parseCase struct {
+	name    string
+	now     time.Time
+	input   string
+	want    time.Time
+	wantErr error
+}
+
+func runParseCases(t *testing.T, language string, cases []parseCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parserAt(t, language, tc.now).ParseRelativeTime(tc.input)
+			switch {
+			case tc.wantErr != nil:
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("ParseRelativeTime(%q) error = %v, want %v", tc.input, err, tc.wantErr)
+				}
+			case tc.want.IsZero():
+				if err == nil {
+					t.Fatalf("ParseRelativeTime(%q) = %v, want an error", tc.input, got)
+				}
+			case err != nil:
+				t.Fatalf("ParseRelativeTime(%q) error = %v", tc.input, err)
+			case !got.Equal(tc.want):
+				t.Fatalf("ParseRelativeTime(%q) = %v, want %v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseEnglish(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	// Wednesday
+	now := time.Date(2024, time.January, 31, 10, 0, 0, 0, ny)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2024, month, day, hour, min, 0, 0, ny)
+	}
+
+	runParseCases(t, "en", []parseCase{
+		{name: "tomorrow", now: now, input: "tomorrow 9:00", want: at(time.February, 1, 9, 0)},
+		{name: "today later", now: now, input: "today 11:00", want: at(time.January, 31, 11, 0)},
+		{name: "today passed", now: now, input: "today 8:00", wantErr: ErrTimeInPast},
+		{name: "today now", now: now, input: "today 10:00", wantErr: ErrTimeInPast},
+		{name: "tonight", now: now, input: "tonight", want: at(time.January, 31, 20, 0)},
+		{name: "tonight with hour", now: now, input: "tonight at 9", want: at(time.January, 31, 21, 0)},
+		{name: "noon", now: now, input: "noon", want: at(time.January, 31, 12, 0)},
+		{name: "midnight", now: now, input: "midnight", want: at(time.February, 1, 0, 0)},
+		{name: "bare pm", now: now, input: "2pm", want: at(time.January, 31, 14, 0)},
+		{name: "bare time passed", now: now, input: "9am", want: at(time.February, 1, 9, 0)},
+		{name: "weekday", now: now, input: "friday 2pm", want: at(time.February, 2, 14, 0)},
+		{name: "weekday today later", now: now, input: "wednesday 11:00", want: at(time.January, 31, 11, 0)},
+		{name: "weekday today passed", now: now, input: "wednesday 9:00", want: at(time.February, 7, 9, 0)},
+		{name: "next weekday today", now: now, input: "next wednesday 11:00", want: at(time.February, 7, 11, 0)},
+		{name: "weekday default hour", now: now, input: "on thursday", want: at(time.February, 1, 9, 0)},
+		{name: "month day", now: now, input: "march 3rd at noon", want: at(time.March, 3, 12, 0)},
+		{name: "day month", now: now, input: "3 march 9am", want: at(time.March, 3, 9, 0)},
+		{name: "date passed this year", now: now, input: "january 15", want: time.Date(2025, time.January, 15, 9, 0, 0, 0, ny)},
+		{name: "date with year", now: now, input: "feb 29 2028 8pm", want: time.Date(2028, time.February, 29, 20, 0, 0, 0, ny)},
+		{name: "february 30", now: now, input: "feb 30"},
+		{name: "no leap day", now: now, input: "february 29 2025"},
+		{name: "invalid hour", now: now, input: "tomorrow 25:00"},
+		{name: "in hours", now: now, input: "in 2 hours", want: at(time.January, 31, 12, 0)},
+		{name: "in compound", now: now, input: "in 1h30m", want: at(time.January, 31, 11, 30)},
+		{name: "after fraction", now: now, input: "after 1.5 hours", want: at(time.January, 31, 11, 30)},
+		{name: "in month from 31st", now: now, input: "in 1 month", want: at(time.February, 29, 10, 0)},
+		{name: "gibberish", now: now, input: "whenever"},
+	})
+}
+
+func TestParseEnglishDST(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	// The day before clocks go forward at 02:00 and back at 02:00
+	springEve := time.Date(2024, time.March, 9, 10, 0, 0, 0, ny)
+	fallEve := time.Date(2024, time.November, 2, 10, 0, 0, 0, ny)
+
+	runParseCases(t, "en", []parseCase{
+		{name: "tomorrow keeps wall clock", now: springEve, input: "tomorrow 9:00",
+			want: time.Date(2024, time.March, 10, 13, 0, 0, 0, time.UTC)},
+		{name: "tomorrow in gap", now: springEve, input: "tomorrow 2:30",
+			want: time.Date(2024, time.March, 10, 7, 30, 0, 0, time.UTC)},
+		{name: "tomorrow repeated", now: fallEve, input: "tomorrow 1:30",
+			want: time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC)},
+		{name: "weekday after change", now: fallEve, input: "monday 9am",
+			want: time.Date(2024, time.November, 4, 14, 0, 0, 0, time.UTC)},
+	})
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+type offsetUnit int
+
+const (
+	unitSecond offsetUnit = iota
+	unitMinute
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+// Offset is a relative time such as "1 month 2 days 3 hours". Months move
+// by calendar months and days by calendar days on the wall clock; the rest
+// is elapsed time.
+type Offset struct {
+	Months   int
+	Days     int
+	Duration time.Duration
+}
+
+// add adds value units to the offset. Fractions of a calendar unit carry
+// over to the next smaller one, with a month counted as 30 days.
+func (o *Offset) add(value float64, unit offsetUnit) {
+	switch unit {
+	case unitSecond:
+		o.Duration += time.Duration(value * float64(time.Second))
+	case unitMinute:
+		o.Duration += time.Duration(value * float64(time.Minute))
+	case unitHour:
+		o.Duration += time.Duration(value * float64(time.Hour))
+	case unitDay:
+		whole, fraction := math.Modf(value)
+		o.Days += int(whole)
+		o.add(fraction*24, unitHour)
+	case unitWeek:
+		o.add(value*7, unitDay)
+	case unitMonth:
+		whole, fraction := math.Modf(value)
+		o.Months += int(whole)
+		o.add(fraction*30, unitDay)
+	case unitYear:
+		o.add(value*12, unitMonth)
+	}
+}
+
+// From returns t moved by the offset in t's location. A month that lacks
+// t's day ends on its last day, so one month after January 31st is the end
+// of February.
+func (o Offset) From(t time.Time) time.Time {
+	if o.Months != 0 || o.Days != 0 {
+		year, month, day := clampDay(t.Year(), t.Month()+time.Month(o.Months), t.Day())
+		t = LocalTime(year, month, day+o.Days, t.Hour(), t.Minute(), t.Second(), t.Location()).
+			Add(time.Duration(t.Nanosecond()))
+	}
+	return t.Add(o.Duration)
+}
+
+// Approximate returns the offset as a fixed duration, counting a month as
+// 30 days and a day as 24 hours.
+func (o Offset) Approximate() time.Duration {
+	return time.Duration(o.Months)*30*24*time.Hour + time.Duration(o.Days)*24*time.Hour + o.Duration
+}
+
+var englishUnits = map[string]offsetUnit{
+	"s": unitSecond, "sec": unitSecond, "secs": unitSecond, "second": unitSecond, "seconds": unitSecond,
+	"m": unitMinute, "min": unitMinute, "mins": unitMinute, "minute": unitMinute, "minutes": unitMinute,
+	"h": unitHour, "hr": unitHour, "hrs": unitHour, "hour": unitHour, "hours": unitHour,
+	"d": unitDay, "day": unitDay, "days": unitDay,
+	"w": unitWeek, "wk": unitWeek, "wks": unitWeek, "week": unitWeek, "weeks": unitWeek,
+	"mo": unitMonth, "month": unitMonth, "months": unitMonth,
+	"y": unitYear, "yr": unitYear, "yrs": unitYear, "year": unitYear, "years": unitYear,
+}
+
+var (
+	englishTermPattern      = regexp.MustCompile(`^(\d+(?:\.\d+)?|an?)\s*([a-z]+)`)
+	englishSeparatorPattern = regexp.MustCompile(`^(?:[\s,]+|and\b)`)
+)
+
+// parseEnglishOffset parses "2 hours", "1 hour 30 minutes", "1h30m",
+// "1.5 hours" or "a month and 2 days".
+func parseEnglishOffset(input string) (Offset, error) {
+	var offset Offset
+	terms := 0
+	for s := input; s != ""; {
+		if separator := englishSeparatorPattern.FindString(s); separator != "" {
+			s = s[len(separator):]
+			continue
+		}
+
+		match := englishTermPattern.FindStringSubmatch(s)
+		if match == nil {
+			return Offset{}, fmt.Errorf("invalid duration: %s", input)
+		}
+		unit, ok := englishUnits[match[2]]
+		if !ok {
+			return Offset{}, fmt.Errorf("unsupported time unit: %s", match[2])
+		}
+		value := 1.0
+		if match[1] != "a" && match[1] != "an" {
+			value, _ = strconv.ParseFloat(match[1], 64)
+		}
+
+		offset.add(value, unit)
+		terms++
+		s = s[len(match[0]):]
+	}
+
+	if terms == 0 {
+		return Offset{}, fmt.Errorf("invalid duration: %s", input)
+	}
+	return offset, nil
+}
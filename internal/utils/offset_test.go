@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEnglishOffset(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    Offset
+		wantErr bool
+	}{
+		{input: "2 hours", want: Offset{Duration: 2 * time.Hour}},
+		{input: "1h30m", want: Offset{Duration: 90 * time.Minute}},
+		{input: "1.5 hours", want: Offset{Duration: 90 * time.Minute}},
+		{input: "1 hour 30 minutes", want: Offset{Duration: 90 * time.Minute}},
+		{input: "1.5 days", want: Offset{Days: 1, Duration: 12 * time.Hour}},
+		{input: "2 weeks", want: Offset{Days: 14}},
+		{input: "1.5 months", want: Offset{Months: 1, Days: 15}},
+		{input: "a month and 2 days", want: Offset{Months: 1, Days: 2}},
+		{input: "1 year", want: Offset{Months: 12}},
+		{input: "", wantErr: true},
+		{input: "2", wantErr: true},
+		{input: "2 parsecs", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseEnglishOffset(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("parseEnglishOffset(%q) = %+v, want an error", tc.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEnglishOffset(%q) error = %v", tc.input, err)
+			}
+			if got != tc.want {
+				t.Fatalf("parseEnglishOffset(%q) = %+v, want %+v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOffsetFrom(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	cases := []struct {
+		name   string
+		from   time.Time
+		offset Offset
+		want   time.Time
+	}{
+		{name: "jan 31 plus a month in a leap year", from: time.Date(2024, time.January, 31, 10, 0, 0, 0, ny),
+			offset: Offset{Months: 1}, want: time.Date(2024, time.February, 29, 10, 0, 0, 0, ny)},
+		{name: "jan 31 plus a month", from: time.Date(2023, time.January, 31, 10, 0, 0, 0, ny),
+			offset: Offset{Months: 1}, want: time.Date(2023, time.February, 28, 10, 0, 0, 0, ny)},
+		{name: "mar 31 plus a month", from: time.Date(2024, time.March, 31, 10, 0, 0, 0, ny),
+			offset: Offset{Months: 1}, want: time.Date(2024, time.April, 30, 10, 0, 0, 0, ny)},
+		{name: "across the year", from: time.Date(2024, time.November, 30, 10, 0, 0, 0, ny),
+			offset: Offset{Months: 3}, want: time.Date(2025, time.February, 28, 10, 0, 0, 0, ny)},
+		{name: "month then days then duration", from: time.Date(2024, time.January, 31, 10, 0, 0, 0, ny),
+			offset: Offset{Months: 1, Days: 2, Duration: 3 * time.Hour}, want: time.Date(2024, time.March, 2, 13, 0, 0, 0, ny)},
+		{name: "day across spring forward keeps wall clock", from: time.Date(2024, time.March, 9, 10, 0, 0, 0, ny),
+			offset: Offset{Days: 1}, want: time.Date(2024, time.March, 10, 10, 0, 0, 0, ny)},
+		{name: "24 hours across spring forward is elapsed", from: time.Date(2024, time.March, 9, 10, 0, 0, 0, ny),
+			offset: Offset{Duration: 24 * time.Hour}, want: time.Date(2024, time.March, 10, 11, 0, 0, 0, ny)},
+		{name: "day across fall back keeps wall clock", from: time.Date(2024, time.November, 2, 10, 0, 0, 0, ny),
+			offset: Offset{Days: 1}, want: time.Date(2024, time.November, 3, 10, 0, 0, 0, ny)},
+		{name: "day into a gap", from: time.Date(2024, time.March, 9, 2, 30, 0, 0, ny),
+			offset: Offset{Days: 1}, want: time.Date(2024, time.March, 10, 7, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.offset.From(tc.from); !got.Equal(tc.want) {
+				t.Fatalf("%+v.From(%v) = %v, want %v", tc.offset, tc.from, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOffsetApproximate(t *testing.T) {
+	offset := Offset{Months: 1, Days: 2, Duration: 3 * time.Hour}
+	if got, want := offset.Approximate(), 32*24*time.Hour+3*time.Hour; got != want {
+		t.Fatalf("Approximate() = %v, want %v", got, want)
+	}
+}
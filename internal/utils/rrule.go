@@ -0,0 +1,453 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ part of an RRULE.
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+	FrequencyYearly  Frequency = "YEARLY"
+)
+
+// rruleSearchYears bounds how far ahead Next looks for a matching day, so a
+// rule such as BYMONTH=2;BYMONTHDAY=30 cannot loop forever.
+const rruleSearchYears = 100
+
+// rruleTimeLayout is the UTC form of an iCalendar DATE-TIME.
+const rruleTimeLayout = "20060102T150405Z"
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// WeekdayNum is a BYDAY entry: a weekday with an optional ordinal, so that
+// 2TU is the second Tuesday and -1FR the last Friday of the month or year.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// RRule is an RFC 5545 recurrence rule. FREQ, INTERVAL, BYDAY, BYMONTHDAY,
+// BYMONTH, COUNT and UNTIL are supported.
+type RRule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	Count      int
+	Until      time.Time
+	// untilDate is set when UNTIL is a DATE, which includes the whole day
+	untilDate bool
+	raw       string
+}
+
+// Recurrence is a recurrence set: a rule anchored at DTSTART, minus the
+// EXDATE exceptions.
+type Recurrence struct {
+	Start   time.Time
+	Rule    *RRule
+	ExDates []time.Time
+	// exDays holds EXDATE values given as DATE, excluding the whole day
+	exDays []string
+}
+
+// ParseRRule parses the value of an RRULE property, with or without the
+// "RRULE:" prefix. A floating UNTIL is taken as wall-clock time in loc.
+func ParseRRule(value string, loc *time.Location) (*RRule, error) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 6 && strings.EqualFold(value[:6], "RRULE:") {
+		value = value[6:]
+	}
+
+	rule := &RRule{Interval: 1, raw: value}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		name = strings.ToUpper(name)
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+			switch rule.Freq {
+			case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", val)
+			}
+		case "INTERVAL":
+			if rule.Interval, err = strconv.Atoi(val); err != nil || rule.Interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+		case "COUNT":
+			if rule.Count, err = strconv.Atoi(val); err != nil || rule.Count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+		case "UNTIL":
+			if rule.Until, rule.untilDate, err = parseICalTime(val, loc); err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", val)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, err := parseWeekdayNum(day)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, month := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(month)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH %q", month)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			// Weeks always start on Monday, the RFC default
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if rule.Count > 0 && !rule.Until.IsZero() {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot both be set")
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != FrequencyMonthly && rule.Freq != FrequencyYearly {
+			return nil, fmt.Errorf("BYDAY ordinals need FREQ=MONTHLY or FREQ=YEARLY")
+		}
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq == FrequencyWeekly {
+		return nil, fmt.Errorf("BYMONTHDAY cannot be used with FREQ=WEEKLY")
+	}
+
+	return rule, nil
+}
+
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+
+	weekday, ok := rruleWeekdays[value[len(value)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+
+	n := 0
+	if ordinal := value[:len(value)-2]; ordinal != "" {
+		var err error
+		if n, err = strconv.Atoi(ordinal); err != nil || n == 0 || n < -53 || n > 53 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+		}
+	}
+
+	return WeekdayNum{Weekday: weekday, N: n}, nil
+}
+
+// parseICalTime parses a DATE-TIME in UTC, a floating DATE-TIME (taken as
+// wall-clock time in loc) or a DATE, reporting whether the value was a DATE.
+func parseICalTime(value string, loc *time.Location) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(rruleTimeLayout, value); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return LocalTime(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), loc), false, nil
+	}
+	t, err := time.Parse("20060102", value)
+	return t, true, err
+}
+
+// propertyLocation returns the zone named by a TZID parameter, or loc when
+// there is none. Other parameters such as VALUE=DATE are implied by the
+// value itself.
+func propertyLocation(params []string, loc *time.Location) (*time.Location, error) {
+	for _, param := range params {
+		name, value, _ := strings.Cut(param, "=")
+		if strings.EqualFold(name, "TZID") {
+			zone, err := time.LoadLocation(strings.Trim(value, `"`))
+			if err != nil {
+				return nil, fmt.Errorf("unknown TZID %q", value)
+			}
+			return zone, nil
+		}
+	}
+	return loc, nil
+}
+
+// ParseRecurrence parses DTSTART, RRULE and EXDATE lines, separated by
+// newlines or spaces. Without a DTSTART line the set starts at start.
+// Floating times are wall-clock times in loc unless a TZID names another
+// zone.
+func ParseRecurrence(text string, start time.Time, loc *time.Location) (*Recurrence, error) {
+	recurrence := &Recurrence{Start: start}
+
+	for _, line := range strings.Fields(text) {
+		property, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence line %q", line)
+		}
+		params := strings.Split(property, ";")
+		name := strings.ToUpper(params[0])
+		zone, err := propertyLocation(params[1:], loc)
+		if err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case "DTSTART":
+			t, _, err := parseICalTime(value, zone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTSTART %q", value)
+			}
+			recurrence.Start = t
+		case "RRULE":
+			if recurrence.Rule != nil {
+				return nil, fmt.Errorf("only one RRULE is supported")
+			}
+			rule, err := ParseRRule(value, loc)
+			if err != nil {
+				return nil, err
+			}
+			recurrence.Rule = rule
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				t, isDate, err := parseICalTime(v, zone)
+				if err != nil {
+					return nil, fmt.Errorf("invalid EXDATE %q", v)
+				}
+				if isDate {
+					recurrence.exDays = append(recurrence.exDays, t.Format("20060102"))
+				} else {
+					recurrence.ExDates = append(recurrence.ExDates, t)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence property %s", name)
+		}
+	}
+
+	if recurrence.Rule == nil {
+		return nil, fmt.Errorf("RRULE is required")
+	}
+	return recurrence, nil
+}
+
+// String formats the set as DTSTART, RRULE and EXDATE lines.
+func (r *Recurrence) String() string {
+	lines := []string{
+		"DTSTART:" + r.Start.UTC().Format(rruleTimeLayout),
+		"RRULE:" + r.Rule.raw,
+	}
+	for _, t := range r.ExDates {
+		lines = append(lines, "EXDATE:"+t.UTC().Format(rruleTimeLayout))
+	}
+	for _, day := range r.exDays {
+		lines = append(lines, "EXDATE;VALUE=DATE:"+day)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Next returns the first occurrence after t, or the zero time when the set
+// has none. Occurrences keep the wall-clock time of DTSTART in loc.
+func (r *Recurrence) Next(t time.Time, loc *time.Location) time.Time {
+	rule := r.Rule
+	start := r.Start.In(loc)
+	hour, minute, second := start.Clock()
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	// Without COUNT there is nothing to tally, so the search can begin at t
+	day := first
+	if rule.Count == 0 {
+		if after := t.In(loc); after.After(start) {
+			day = time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, time.UTC)
+		}
+	}
+
+	seen := 0
+	limit := day.AddDate(rruleSearchYears, 0, 0)
+	for ; day.Before(limit); day = day.AddDate(0, 0, 1) {
+		if !rule.inPeriod(first, day) || !rule.matches(start, day) {
+			continue
+		}
+
+		at := LocalTime(day.Year(), day.Month(), day.Day(), hour, minute, second, loc)
+		if at.Before(start) {
+			continue
+		}
+		if rule.pastUntil(at, day) {
+			return time.Time{}
+		}
+
+		seen++
+		if rule.Count > 0 && seen > rule.Count {
+			return time.Time{}
+		}
+		if at.After(t) && !r.excluded(at, day) {
+			return at
+		}
+	}
+
+	return time.Time{}
+}
+
+func (r *Recurrence) excluded(at, day time.Time) bool {
+	for _, ex := range r.ExDates {
+		if ex.Equal(at) {
+			return true
+		}
+	}
+	date := day.Format("20060102")
+	for _, ex := range r.exDays {
+		if ex == date {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule *RRule) pastUntil(at, day time.Time) bool {
+	if rule.Until.IsZero() {
+		return false
+	}
+	if rule.untilDate {
+		return day.After(rule.Until)
+	}
+	return at.After(rule.Until)
+}
+
+// inPeriod reports whether day falls in a period the interval selects,
+// counting periods from the one that holds first.
+func (rule *RRule) inPeriod(first, day time.Time) bool {
+	if rule.Interval == 1 {
+		return true
+	}
+
+	var n int
+	switch rule.Freq {
+	case FrequencyDaily:
+		n = int(day.Sub(first).Hours() / 24)
+	case FrequencyWeekly:
+		n = int(weekStart(day).Sub(weekStart(first)).Hours() / (24 * 7))
+	case FrequencyMonthly:
+		n = (day.Year()-first.Year())*12 + int(day.Month()-first.Month())
+	case FrequencyYearly:
+		n = day.Year() - first.Year()
+	}
+	return n%rule.Interval == 0
+}
+
+// matches applies the BY* parts to day, falling back to the day and month of
+// start where the frequency needs them.
+func (rule *RRule) matches(start, day time.Time) bool {
+	if len(rule.ByMonth) > 0 && !containsMonth(rule.ByMonth, day.Month()) {
+		return false
+	}
+	if len(rule.ByMonthDay) > 0 && !rule.matchesMonthDay(day) {
+		return false
+	}
+	if len(rule.ByDay) > 0 && !rule.matchesWeekday(day) {
+		return false
+	}
+
+	restricted := len(rule.ByDay) > 0 || len(rule.ByMonthDay) > 0
+	switch rule.Freq {
+	case FrequencyWeekly:
+		return restricted || day.Weekday() == start.Weekday()
+	case FrequencyMonthly:
+		return restricted || day.Day() == start.Day()
+	case FrequencyYearly:
+		if restricted {
+			return true
+		}
+		if len(rule.ByMonth) == 0 && day.Month() != start.Month() {
+			return false
+		}
+		return day.Day() == start.Day()
+	}
+	return true
+}
+
+func (rule *RRule) matchesMonthDay(day time.Time) bool {
+	last := daysIn(day.Year(), day.Month())
+	for _, n := range rule.ByMonthDay {
+		if n == day.Day() || (n < 0 && last+n+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWeekday checks BYDAY; ordinals count within the month, or within
+// the year for a yearly rule without BYMONTH.
+func (rule *RRule) matchesWeekday(day time.Time) bool {
+	inYear := rule.Freq == FrequencyYearly && len(rule.ByMonth) == 0
+
+	for _, weekday := range rule.ByDay {
+		if weekday.Weekday != day.Weekday() {
+			continue
+		}
+		if weekday.N == 0 {
+			return true
+		}
+
+		index, length := day.Day(), daysIn(day.Year(), day.Month())
+		if inYear {
+			index, length = day.YearDay(), time.Date(day.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		}
+		if weekday.N > 0 && (index-1)/7+1 == weekday.N {
+			return true
+		}
+		if weekday.N < 0 && -((length-index)/7+1) == weekday.N {
+			return true
+		}
+	}
+	return false
+}
+
+// weekStart returns the Monday of the week that holds day.
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
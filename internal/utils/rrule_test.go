@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	start := time.Date(2024, time.January, 1, 9, 0, 0, 0, ny)
+	cases := []struct {
+		text    string
+		wantErr bool
+	}{
+		{text: "RRULE:FREQ=MONTHLY;BYDAY=-1FR"},
+		{text: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;WKST=MO"},
+		{text: "DTSTART;TZID=Europe/London:20240101T090000 RRULE:FREQ=DAILY;COUNT=5"},
+		{text: "RRULE:FREQ=DAILY EXDATE:20240102T140000Z,20240103T140000Z"},
+		{text: "RRULE:FREQ=DAILY EXDATE;VALUE=DATE:20240102"},
+		{text: "", wantErr: true},
+		{text: "EXDATE:20240102T140000Z", wantErr: true},
+		{text: "RRULE:FREQ=SECONDLY", wantErr: true},
+		{text: "RRULE:INTERVAL=2", wantErr: true},
+		{text: "RRULE:FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{text: "RRULE:FREQ=DAILY;COUNT=3;UNTIL=20240110", wantErr: true},
+		{text: "RRULE:FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{text: "RRULE:FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{text: "RRULE:FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{text: "RRULE:FREQ=DAILY RRULE:FREQ=WEEKLY", wantErr: true},
+		{text: "DTSTART;TZID=Mars/Olympus:20240101T090000 RRULE:FREQ=DAILY", wantErr: true},
+		{text: "RRULE:FREQ=DAILY EXDATE:tomorrow", wantErr: true},
+		{text: "RDATE:20240102 RRULE:FREQ=DAILY", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			_, err := ParseRecurrence(tc.text, start, ny)
+			if tc.wantErr && err == nil {
+				t.Fatalf("ParseRecurrence(%q) succeeded, want an error", tc.text)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("ParseRecurrence(%q) error = %v", tc.text, err)
+			}
+		})
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	date := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, ny)
+	}
+	// Monday
+	start := date(2024, time.January, 1, 9, 0)
+	cases := []struct {
+		name  string
+		text  string
+		start time.Time
+		after time.Time
+		want  time.Time
+	}{
+		{name: "last friday", text: "RRULE:FREQ=MONTHLY;BYDAY=-1FR", start: start,
+			after: start, want: date(2024, time.January, 26, 9, 0)},
+		{name: "last friday next month", text: "RRULE:FREQ=MONTHLY;BYDAY=-1FR", start: start,
+			after: date(2024, time.January, 26, 9, 0), want: date(2024, time.February, 23, 9, 0)},
+		{name: "second tuesday", text: "RRULE:FREQ=MONTHLY;BYDAY=2TU", start: start,
+			after: start, want: date(2024, time.January, 9, 9, 0)},
+		{name: "every other week", text: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", start: start,
+			after: date(2024, time.January, 3, 9, 0), want: date(2024, time.January, 15, 9, 0)},
+		{name: "last day of the month", text: "RRULE:FREQ=MONTHLY;BYMONTHDAY=-1", start: start,
+			after: date(2024, time.January, 31, 9, 0), want: date(2024, time.February, 29, 9, 0)},
+		{name: "yearly by month", text: "RRULE:FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=1", start: start,
+			after: date(2024, time.March, 1, 9, 0), want: date(2025, time.March, 1, 9, 0)},
+		{name: "count includes the start", text: "RRULE:FREQ=DAILY;COUNT=3", start: start,
+			after: date(2024, time.January, 2, 9, 0), want: date(2024, time.January, 3, 9, 0)},
+		{name: "count exhausted", text: "RRULE:FREQ=DAILY;COUNT=3", start: start,
+			after: date(2024, time.January, 3, 9, 0)},
+		{name: "until date includes the day", text: "RRULE:FREQ=DAILY;UNTIL=20240103", start: start,
+			after: date(2024, time.January, 2, 9, 0), want: date(2024, time.January, 3, 9, 0)},
+		{name: "until date passed", text: "RRULE:FREQ=DAILY;UNTIL=20240103", start: start,
+			after: date(2024, time.January, 3, 9, 0)},
+		{name: "floating until is local", text: "RRULE:FREQ=DAILY;UNTIL=20240103T090000", start: start,
+			after: date(2024, time.January, 2, 9, 0), want: date(2024, time.January, 3, 9, 0)},
+		{name: "utc exdate", text: "RRULE:FREQ=DAILY EXDATE:20240102T140000Z", start: start,
+			after: start, want: date(2024, time.January, 3, 9, 0)},
+		{name: "floating exdate is local", text: "RRULE:FREQ=DAILY EXDATE:20240102T090000", start: start,
+			after: start, want: date(2024, time.January, 3, 9, 0)},
+		{name: "date exdate", text: "RRULE:FREQ=DAILY EXDATE;VALUE=DATE:20240102", start: start,
+			after: start, want: date(2024, time.January, 3, 9, 0)},
+		{name: "exdate in another zone", text: "RRULE:FREQ=DAILY EXDATE;TZID=Europe/London:20240102T140000", start: start,
+			after: start, want: date(2024, time.January, 3, 9, 0)},
+		{name: "dtstart in another zone", text: "DTSTART;TZID=Europe/London:20240101T140000 RRULE:FREQ=DAILY", start: start,
+			after: start, want: date(2024, time.January, 2, 9, 0)},
+		{name: "keeps wall clock across spring forward", text: "RRULE:FREQ=DAILY", start: date(2024, time.March, 9, 9, 0),
+			after: date(2024, time.March, 9, 9, 0), want: date(2024, time.March, 10, 9, 0)},
+		{name: "gap moves forward", text: "RRULE:FREQ=DAILY", start: date(2024, time.March, 9, 2, 30),
+			after: date(2024, time.March, 9, 2, 30), want: time.Date(2024, time.March, 10, 7, 30, 0, 0, time.UTC)},
+		{name: "february 30 never happens", text: "RRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", start: start,
+			after: start},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(tc.text, tc.start, ny)
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q) error = %v", tc.text, err)
+			}
+			if got := recurrence.Next(tc.after, ny); !got.Equal(tc.want) {
+				t.Fatalf("Next(%v) = %v, want %v", tc.after, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package utils
+
+// Truncate shortens s to at most max characters followed by "...". It
+// counts runes, so Arabic or Japanese text is never cut inside a character.
+func Truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
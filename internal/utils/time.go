@@ -1,14 +1,20 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 )
 
+// ErrTimeInPast is returned for a phrase such as "today 8:00" that names a
+// time that has already passed.
+var ErrTimeInPast = errors.New("time is in the past")
+
 type TimeParser struct {
 	timezone *time.Location
+	clock    func() time.Time
+	language string
 }
 
 func NewTimeParser(timezone string) (*TimeParser, error) {
@@ -19,53 +25,73 @@ func NewTimeParser(timezone string) (*TimeParser, error) {
 	return &TimeParser{timezone: loc}, nil
 }
 
-func (tp *TimeParser) ParseRelativeTime(input string) (time.Time, error) {
-	input = strings.ToLower(strings.TrimSpace(input))
-	now := time.Now().In(tp.timezone)
+// SetLanguage makes the parser try phrases in the given language, such as
+// "ar" or "ja", before the others.
+func (tp *TimeParser) SetLanguage(language string) {
+	tp.language = language
+}
 
-	if strings.Contains(input, "after") || strings.Contains(input, "بعد") {
-		return tp.parseAfterTime(input, now)
-	}
+// SetClock replaces the source of the current time that relative phrases
+// are resolved against.
+func (tp *TimeParser) SetClock(clock func() time.Time) {
+	tp.clock = clock
+}
 
-	return tp.parseAbsoluteTime(input, now)
+// Now returns the current time in the parser's timezone.
+func (tp *TimeParser) Now() time.Time {
+	if tp.clock != nil {
+		return tp.clock().In(tp.timezone)
+	}
+	return time.Now().In(tp.timezone)
 }
 
-func (tp *TimeParser) parseAfterTime(input string, now time.Time) (time.Time, error) {
-	input = strings.ReplaceAll(input, "after", "")
-	input = strings.ReplaceAll(input, "بعد", "")
-	input = strings.TrimSpace(input)
+// ParseRelativeTime parses a time such as "2024-01-01 15:30", "tomorrow
+// 9:00", "غداً الساعة ٩" or "明日の9時". Phrases in the parser's language
+// are tried first, then those of the other languages.
+func (tp *TimeParser) ParseRelativeTime(input string) (time.Time, error) {
+	input = normalizeDigits(strings.ToLower(strings.TrimSpace(input)))
+	now := tp.Now()
 
-	parts := strings.Fields(input)
-	if len(parts) < 2 {
-		return time.Time{}, fmt.Errorf("invalid relative time format")
+	if t, err := tp.parseAbsoluteTime(input, now); err == nil {
+		return t, nil
 	}
 
-	num, err := strconv.Atoi(parts[0])
-	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid number: %s", parts[0])
+	var firstErr error
+	for _, locale := range orderedLocales(tp.language) {
+		t, err := locale.parseTime(input, now)
+		if err == nil {
+			return t, nil
+		}
+		if errors.Is(err, ErrTimeInPast) {
+			return time.Time{}, err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
 	}
+	return time.Time{}, firstErr
+}
 
-	unit := strings.ToLower(parts[1])
-	var duration time.Duration
-
-	switch {
-	case strings.Contains(unit, "minute") || strings.Contains(unit, "دقيقة"):
-		duration = time.Duration(num) * time.Minute
-	case strings.Contains(unit, "hour") || strings.Contains(unit, "ساعة"):
-		duration = time.Duration(num) * time.Hour
-	case strings.Contains(unit, "day") || strings.Contains(unit, "يوم"):
-		duration = time.Duration(num) * 24 * time.Hour
-	case strings.Contains(unit, "week") || strings.Contains(unit, "أسبوع"):
-		duration = time.Duration(num) * 7 * 24 * time.Hour
-	case strings.Contains(unit, "month") || strings.Contains(unit, "شهر"):
-		duration = time.Duration(num) * 30 * 24 * time.Hour
-	case strings.Contains(unit, "year") || strings.Contains(unit, "سنة"):
-		duration = time.Duration(num) * 365 * 24 * time.Hour
-	default:
-		return time.Time{}, fmt.Errorf("unsupported time unit: %s", unit)
+// ParseDuration parses a Go duration such as "90m" or a spoken one such as
+// "1 hour 30 minutes", "ساعتين ونصف" or "3時間". Months count as 30 days
+// and days as 24 hours.
+func ParseDuration(input string) (time.Duration, error) {
+	input = normalizeDigits(strings.ToLower(strings.TrimSpace(input)))
+	if d, err := time.ParseDuration(input); err == nil {
+		return d, nil
 	}
 
-	return now.Add(duration), nil
+	var firstErr error
+	for _, locale := range orderedLocales("") {
+		offset, err := locale.parseOffset(input)
+		if err == nil {
+			return offset.Approximate(), nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return 0, firstErr
 }
 
 func (tp *TimeParser) parseAbsoluteTime(input string, now time.Time) (time.Time, error) {
@@ -82,10 +108,12 @@ func (tp *TimeParser) parseAbsoluteTime(input string, now time.Time) (time.Time,
 		if t, err := time.ParseInLocation(format, input, tp.timezone); err == nil {
 			// If only time is provided, assume it's for today or tomorrow
 			if format == "15:04" {
-				today := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, tp.timezone)
+				local := now.In(tp.timezone)
+				year, month, day := local.Date()
+				today := LocalTime(year, month, day, t.Hour(), t.Minute(), 0, tp.timezone)
 				if today.Before(now) {
 					// If the time has passed today, schedule for tomorrow
-					today = today.Add(24 * time.Hour)
+					today = LocalTime(year, month, day+1, t.Hour(), t.Minute(), 0, tp.timezone)
 				}
 				return today, nil
 			}
@@ -110,16 +138,8 @@ func FormatDuration(d time.Duration) string {
 }
 
 func GetNextRecurrenceTime(baseTime time.Time, recurrenceType string, count int) time.Time {
-	switch recurrenceType {
-	case "daily":
-		return baseTime.AddDate(0, 0, count)
-	case "weekly":
-		return baseTime.AddDate(0, 0, count*7)
-	case "monthly":
-		return baseTime.AddDate(0, count, 0)
-	case "yearly":
-		return baseTime.AddDate(count, 0, 0)
-	default:
-		return baseTime
+	if next := NextRecurrence(baseTime, recurrenceType, count, baseTime, baseTime.Location()); !next.IsZero() {
+		return next
 	}
+	return baseTime
 }
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAbsoluteTime(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	now := time.Date(2024, time.January, 31, 10, 0, 0, 0, ny)
+	springEve := time.Date(2024, time.March, 9, 10, 0, 0, 0, ny)
+	fallEve := time.Date(2024, time.November, 2, 10, 0, 0, 0, ny)
+
+	runParseCases(t, "en", []parseCase{
+		{name: "date and time", now: now, input: "2024-06-01 15:30", want: time.Date(2024, time.June, 1, 15, 30, 0, 0, ny)},
+		{name: "with seconds", now: now, input: "2024-06-01 15:30:45", want: time.Date(2024, time.June, 1, 15, 30, 45, 0, ny)},
+		{name: "day first", now: now, input: "01/06/2024 15:30", want: time.Date(2024, time.June, 1, 15, 30, 0, 0, ny)},
+		{name: "time first", now: now, input: "15:30 01/06/2024", want: time.Date(2024, time.June, 1, 15, 30, 0, 0, ny)},
+		{name: "date only", now: now, input: "2024-06-01", want: time.Date(2024, time.June, 1, 0, 0, 0, 0, ny)},
+		{name: "clock later today", now: now, input: "11:00", want: time.Date(2024, time.January, 31, 11, 0, 0, 0, ny)},
+		{name: "clock passed", now: now, input: "09:00", want: time.Date(2024, time.February, 1, 9, 0, 0, 0, ny)},
+		{name: "clock across spring forward", now: springEve, input: "09:00",
+			want: time.Date(2024, time.March, 10, 13, 0, 0, 0, time.UTC)},
+		{name: "clock across fall back", now: fallEve, input: "09:00",
+			want: time.Date(2024, time.November, 3, 14, 0, 0, 0, time.UTC)},
+		{name: "clock in gap", now: springEve, input: "02:30",
+			want: time.Date(2024, time.March, 10, 7, 30, 0, 0, time.UTC)},
+		{name: "arabic digits", now: now, input: "٢٠٢٤-٠٦-٠١ ١٥:٣٠", want: time.Date(2024, time.June, 1, 15, 30, 0, 0, ny)},
+	})
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "90m", want: 90 * time.Minute},
+		{input: "1h30m", want: 90 * time.Minute},
+		{input: "1.5 hours", want: 90 * time.Minute},
+		{input: "1 hour 30 minutes", want: 90 * time.Minute},
+		{input: "1 hour, 30 minutes", want: 90 * time.Minute},
+		{input: "an hour and 15 mins", want: 75 * time.Minute},
+		{input: "2 weeks", want: 14 * 24 * time.Hour},
+		{input: "a month and 2 days", want: 32 * 24 * time.Hour},
+		{input: "ساعتين ونصف", want: 150 * time.Minute},
+		{input: "٣ ساعات", want: 3 * time.Hour},
+		{input: "3時間", want: 3 * time.Hour},
+		{input: "1時間半", want: 90 * time.Minute},
+		{input: "", wantErr: true},
+		{input: "soon", wantErr: true},
+		{input: "2 fortnights", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseDuration(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ParseDuration(%q) = %v, want an error", tc.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDuration(%q) error = %v", tc.input, err)
+			}
+			if got != tc.want {
+				t.Fatalf("ParseDuration(%q) = %v, want %v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
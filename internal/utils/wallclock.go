@@ -0,0 +1,118 @@
+package utils
+
+import "time"
+
+// LocalTime returns the instant a wall-clock time names in loc. A time
+// skipped by a forward DST transition moves forward by the length of the
+// gap, so 02:30 on a spring-forward night becomes 03:30. A time that occurs
+// twice when clocks go back resolves to the first of the two.
+func LocalTime(year int, month time.Month, day, hour, min, sec int, loc *time.Location) time.Time {
+	wall := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+
+	// Any transition near wall lies between the offsets a day either side
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var found time.Time
+	for _, offset := range []int{before, after} {
+		candidate := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if sameWallClock(candidate, wall) && (found.IsZero() || candidate.Before(found)) {
+			found = candidate
+		}
+	}
+	if !found.IsZero() {
+		return found
+	}
+
+	// In a gap the offset from before the transition still applies
+	return wall.Add(-time.Duration(before) * time.Second).In(loc)
+}
+
+func sameWallClock(t, wall time.Time) bool {
+	y, m, d := t.Date()
+	hh, mm, ss := t.Clock()
+	return y == wall.Year() && m == wall.Month() && d == wall.Day() &&
+		hh == wall.Hour() && mm == wall.Minute() && ss == wall.Second()
+}
+
+// NextRecurrence returns the first occurrence after t of a recurrence that
+// repeats every interval units of recurrenceType. Minutely and hourly
+// occurrences are a fixed duration apart. Longer units keep the wall-clock
+// time of start in loc; a monthly or yearly occurrence whose day the month
+// lacks falls on the last day of that month, and the next one returns to the
+// original day.
+func NextRecurrence(start time.Time, recurrenceType string, interval int, t time.Time, loc *time.Location) time.Time {
+	if interval < 1 {
+		return time.Time{}
+	}
+
+	var step time.Duration
+	switch recurrenceType {
+	case "minutely":
+		step = time.Duration(interval) * time.Minute
+	case "hourly":
+		step = time.Duration(interval) * time.Hour
+	}
+	if step > 0 {
+		if start.After(t) {
+			return start
+		}
+		return start.Add((t.Sub(start)/step + 1) * step)
+	}
+
+	s := start.In(loc)
+	occurrence := func(k int) time.Time {
+		year, month, day := s.Date()
+		switch recurrenceType {
+		case "daily":
+			day += k * interval
+		case "weekly":
+			day += 7 * k * interval
+		case "monthly":
+			month += time.Month(k * interval)
+			year, month, day = clampDay(year, month, day)
+		case "yearly":
+			year += k * interval
+			year, month, day = clampDay(year, month, day)
+		}
+		// Normalize day overflow before resolving the wall clock
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return LocalTime(date.Year(), date.Month(), date.Day(), s.Hour(), s.Minute(), s.Second(), loc)
+	}
+
+	// Start a little before the estimate and walk forward
+	k := 0
+	after := t.In(loc)
+	switch recurrenceType {
+	case "daily":
+		k = int(after.Sub(s).Hours()/24) / interval
+	case "weekly":
+		k = int(after.Sub(s).Hours()/(24*7)) / interval
+	case "monthly":
+		k = ((after.Year()-s.Year())*12 + int(after.Month()-s.Month())) / interval
+	case "yearly":
+		k = (after.Year() - s.Year()) / interval
+	default:
+		return time.Time{}
+	}
+	if k -= 2; k < 0 {
+		k = 0
+	}
+
+	for {
+		if next := occurrence(k); next.After(t) {
+			return next
+		}
+		k++
+	}
+}
+
+// clampDay moves day to the last day of the month when the month is shorter,
+// normalizing a month past December into the following years.
+func clampDay(year int, month time.Month, day int) (int, time.Month, int) {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	return first.Year(), first.Month(), day
+}
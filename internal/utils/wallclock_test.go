@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocalTime(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	cases := []struct {
+		name                string
+		year                int
+		month               time.Month
+		day, hour, min, sec int
+		want                time.Time
+	}{
+		{name: "standard time", year: 2024, month: time.January, day: 15, hour: 9,
+			want: time.Date(2024, time.January, 15, 14, 0, 0, 0, time.UTC)},
+		{name: "daylight time", year: 2024, month: time.July, day: 15, hour: 9,
+			want: time.Date(2024, time.July, 15, 13, 0, 0, 0, time.UTC)},
+		{name: "gap moves forward", year: 2024, month: time.March, day: 10, hour: 2, min: 30,
+			want: time.Date(2024, time.March, 10, 7, 30, 0, 0, time.UTC)},
+		{name: "after the gap", year: 2024, month: time.March, day: 10, hour: 3, min: 30,
+			want: time.Date(2024, time.March, 10, 7, 30, 0, 0, time.UTC)},
+		{name: "repeated takes the first", year: 2024, month: time.November, day: 3, hour: 1, min: 30,
+			want: time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC)},
+		{name: "after the repeat", year: 2024, month: time.November, day: 3, hour: 2,
+			want: time.Date(2024, time.November, 3, 7, 0, 0, 0, time.UTC)},
+		{name: "day overflow", year: 2024, month: time.January, day: 32, hour: 9,
+			want: time.Date(2024, time.February, 1, 14, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := LocalTime(tc.year, tc.month, tc.day, tc.hour, tc.min, tc.sec, ny)
+			if !got.Equal(tc.want) {
+				t.Fatalf("LocalTime = %v, want %v", got, tc.want)
+			}
+			if got.Location() != ny {
+				t.Fatalf("LocalTime location = %v, want %v", got.Location(), ny)
+			}
+		})
+	}
+}
+
+func TestNextRecurrence(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	date := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, ny)
+	}
+	cases := []struct {
+		name           string
+		start          time.Time
+		recurrenceType string
+		interval       int
+		after          time.Time
+		want           time.Time
+	}{
+		{name: "start in the future", start: date(2024, time.March, 1, 9, 0), recurrenceType: "daily", interval: 1,
+			after: date(2024, time.January, 1, 0, 0), want: date(2024, time.March, 1, 9, 0)},
+		{name: "daily across spring forward", start: date(2024, time.March, 9, 9, 0), recurrenceType: "daily", interval: 1,
+			after: date(2024, time.March, 9, 9, 0), want: date(2024, time.March, 10, 9, 0)},
+		{name: "daily across fall back", start: date(2024, time.November, 2, 9, 0), recurrenceType: "daily", interval: 1,
+			after: date(2024, time.November, 2, 9, 0), want: date(2024, time.November, 3, 9, 0)},
+		{name: "hourly is elapsed time", start: date(2024, time.March, 10, 0, 0), recurrenceType: "hourly", interval: 1,
+			after: date(2024, time.March, 10, 1, 30), want: date(2024, time.March, 10, 3, 0)},
+		{name: "every 90 minutes", start: date(2024, time.January, 1, 9, 0), recurrenceType: "minutely", interval: 90,
+			after: date(2024, time.January, 1, 12, 0), want: date(2024, time.January, 1, 13, 30)},
+		{name: "every other week", start: date(2024, time.January, 1, 9, 0), recurrenceType: "weekly", interval: 2,
+			after: date(2024, time.January, 10, 0, 0), want: date(2024, time.January, 15, 9, 0)},
+		{name: "monthly from the 31st clamps", start: date(2024, time.January, 31, 10, 0), recurrenceType: "monthly", interval: 1,
+			after: date(2024, time.January, 31, 10, 0), want: date(2024, time.February, 29, 10, 0)},
+		{name: "monthly returns to the 31st", start: date(2024, time.January, 31, 10, 0), recurrenceType: "monthly", interval: 1,
+			after: date(2024, time.February, 29, 10, 0), want: date(2024, time.March, 31, 10, 0)},
+		{name: "yearly from a leap day", start: date(2024, time.February, 29, 10, 0), recurrenceType: "yearly", interval: 1,
+			after: date(2024, time.February, 29, 10, 0), want: date(2025, time.February, 28, 10, 0)},
+		{name: "zero interval", start: date(2024, time.January, 1, 9, 0), recurrenceType: "daily", interval: 0,
+			after: date(2024, time.January, 1, 9, 0)},
+		{name: "unknown type", start: date(2024, time.January, 1, 9, 0), recurrenceType: "fortnightly", interval: 1,
+			after: date(2024, time.January, 1, 9, 0)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NextRecurrence(tc.start, tc.recurrenceType, tc.interval, tc.after, ny)
+			if !got.Equal(tc.want) {
+				t.Fatalf("NextRecurrence = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}